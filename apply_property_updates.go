@@ -0,0 +1,43 @@
+package omni
+
+// PropertyUpdate describes a single property change addressed by atom ID.
+type PropertyUpdate struct {
+	ID    string
+	Key   string
+	Value string
+}
+
+// ApplyPropertyUpdates applies each update to the atom with the matching ID
+// in the tree rooted at root, and returns how many updates were applied.
+//
+// Business logic:
+// - Builds an Index of the tree once, so each update is an O(1) lookup
+// - Updates for unknown IDs are skipped
+// - Updates an *Atom rejects (see SetChecked) are skipped and not counted
+// - Updates are applied in order, so later updates to the same key win
+// - A nil root applies nothing
+func ApplyPropertyUpdates(root AtomInterface, updates []PropertyUpdate) int {
+	if root == nil || len(updates) == 0 {
+		return 0
+	}
+
+	index := BuildIndex(root)
+	applied := 0
+
+	for _, update := range updates {
+		atom := index.Get(update.ID)
+		if atom == nil {
+			continue
+		}
+		if target, ok := AsAtom(atom); ok {
+			if err := target.SetChecked(update.Key, update.Value); err != nil {
+				continue
+			}
+		} else {
+			atom.Set(update.Key, update.Value)
+		}
+		applied++
+	}
+
+	return applied
+}
@@ -0,0 +1,63 @@
+package omni
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestApplyPropertyUpdates(t *testing.T) {
+	root := NewAtom("root", WithID("root"))
+	a := NewAtom("node", WithID("a"))
+	a.ChildAdd(NewAtom("leaf", WithID("a1")))
+	root.ChildAdd(a)
+	root.ChildAdd(NewAtom("node", WithID("b")))
+
+	applied := ApplyPropertyUpdates(root, []PropertyUpdate{
+		{ID: "root", Key: "title", Value: "Root"},
+		{ID: "a1", Key: "color", Value: "red"},
+		{ID: "missing", Key: "x", Value: "y"},
+		{ID: "b", Key: "size", Value: "1"},
+		{ID: "b", Key: "size", Value: "2"},
+	})
+
+	if applied != 4 {
+		t.Fatalf("expected 4 applied updates, got %d", applied)
+	}
+	if root.Get("title") != "Root" {
+		t.Fatalf("root title = %q, want Root", root.Get("title"))
+	}
+	if got := FindAtomByID(root, "a1").Get("color"); got != "red" {
+		t.Fatalf("a1 color = %q, want red", got)
+	}
+	if got := FindAtomByID(root, "b").Get("size"); got != "2" {
+		t.Fatalf("b size = %q, want 2", got)
+	}
+}
+
+func TestApplyPropertyUpdates_NilRoot(t *testing.T) {
+	if applied := ApplyPropertyUpdates(nil, []PropertyUpdate{{ID: "a", Key: "k", Value: "v"}}); applied != 0 {
+		t.Fatalf("expected 0 applied updates for nil root, got %d", applied)
+	}
+}
+
+func TestApplyPropertyUpdates_SkipsRejectedWrites(t *testing.T) {
+	reject := func(key string) (string, error) {
+		if key == "bad" {
+			return "", errors.New("reserved key")
+		}
+		return key, nil
+	}
+	root := NewAtom("root", WithID("root"), WithKeyValidator(reject))
+
+	applied := ApplyPropertyUpdates(root, []PropertyUpdate{
+		{ID: "root", Key: "bad", Value: "x"},
+		{ID: "root", Key: "good", Value: "y"},
+	})
+
+	if applied != 1 {
+		t.Fatalf("expected 1 applied update, got %d", applied)
+	}
+	if root.Has("bad") || root.Get("good") != "y" {
+		t.Fatalf("unexpected properties %v", root.GetAll())
+	}
+}
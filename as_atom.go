@@ -0,0 +1,12 @@
+package omni
+
+// AsAtom safely converts an AtomInterface to the concrete *Atom type.
+// It returns false if a is nil or is a different implementation of
+// AtomInterface, instead of panicking like a forced type assertion would.
+func AsAtom(a AtomInterface) (*Atom, bool) {
+	atom, ok := a.(*Atom)
+	if !ok || atom == nil {
+		return nil, false
+	}
+	return atom, true
+}
@@ -0,0 +1,38 @@
+package omni
+
+import "testing"
+
+// customAtom is a minimal AtomInterface implementation that is not *Atom.
+type customAtom struct {
+	AtomInterface
+}
+
+func TestAsAtom_WithAtom(t *testing.T) {
+	a := NewAtom("test", WithID("a1"))
+	got, ok := AsAtom(a)
+	if !ok || got == nil {
+		t.Fatalf("expected ok=true for *Atom, got ok=%v", ok)
+	}
+	if got.GetID() != "a1" {
+		t.Fatalf("expected id a1, got %q", got.GetID())
+	}
+}
+
+func TestAsAtom_WithCustomImplementation(t *testing.T) {
+	c := customAtom{AtomInterface: NewAtom("test")}
+	got, ok := AsAtom(c)
+	if ok || got != nil {
+		t.Fatalf("expected ok=false for custom implementation, got ok=%v", ok)
+	}
+}
+
+func TestAsAtom_WithNil(t *testing.T) {
+	if got, ok := AsAtom(nil); ok || got != nil {
+		t.Fatalf("expected ok=false for nil, got ok=%v", ok)
+	}
+
+	var nilAtom *Atom
+	if got, ok := AsAtom(nilAtom); ok || got != nil {
+		t.Fatalf("expected ok=false for typed nil *Atom, got ok=%v", ok)
+	}
+}
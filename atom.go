@@ -5,7 +5,25 @@ import (
 	"encoding/gob"
 	"encoding/json"
 	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
+	"unsafe"
+)
+
+// Approximate sizes used by MemoryUsage.
+const (
+	// mapHeaderSize is the approximate size of the runtime map header.
+	mapHeaderSize = 48
+	// mapEntryOverhead is the approximate per-entry bookkeeping of a map
+	// (control/tophash bytes and load-factor slack).
+	mapEntryOverhead = 8
+	// stringHeaderSize is the size of a string header (pointer + length).
+	stringHeaderSize = int(unsafe.Sizeof(""))
+	// interfaceSize is the size of an interface value (type + data pointers).
+	interfaceSize = int(unsafe.Sizeof(AtomInterface(nil)))
 )
 
 // Atom is the main implementation of AtomInterface using map[string]string for properties.
@@ -14,17 +32,72 @@ import (
 // There are two special properties:
 // - "id": the unique identifier of the atom
 // - "type": the type of the atom
+//
+// The id, type and properties are guarded by mu, while children are guarded
+// by childrenMu, so property access and children access do not block each other.
+// When both locks are needed, mu is always acquired before childrenMu.
 type Atom struct {
 	id         string
 	atomType   string
 	properties map[string]string
 	children   []AtomInterface
 	mu         sync.RWMutex
+	childrenMu sync.RWMutex
+
+	// Optional per-key property history, enabled by WithPropertyHistory
+	history      map[string][]string
+	historyLimit int
+
+	// Optional key validation/normalization, enabled by WithKeyValidator
+	keyValidator func(key string) (string, error)
+
+	// Optional string interning of property keys and values, enabled by WithInterner
+	interner *Interner
+
+	// Optional value length limit, enabled by WithMaxValueLength
+	maxValueLength    int
+	valueLengthPolicy ValueLengthPolicy
+
+	// parent is the *Atom this atom was last added to as a child, or nil.
+	// It is maintained automatically and guarded by parentMu, which is never
+	// held while acquiring another lock. See Parent.
+	parent   *Atom
+	parentMu sync.Mutex
+
+	// keyWatchers are the callbacks registered with OnChangeKey, by key.
+	// They are guarded by watchersMu, which is never held while acquiring
+	// another lock or calling a watcher.
+	keyWatchers map[string][]*keyWatcher
+	watchersMu  sync.Mutex
+
+	// uniqueChildIDs, enabled by WithUniqueChildIDs, makes all add
+	// operations skip children whose ID is already used by a child.
+	// It is guarded by childrenMu.
+	uniqueChildIDs bool
+}
+
+// init registers the Atom type with the gob package once,
+// instead of on every encode/decode call.
+func init() {
+	gob.Register(&Atom{})
 }
 
 // FromGob decodes the atom from gob-encoded data.
 // This method satisfies the AtomInterface requirement.
+// Atoms nested more than DefaultMaxDepth levels deep are rejected, and the
+// decoded properties go through the atom's property options like any other
+// write; on error the atom is left unchanged.
 func (a *Atom) FromGob(data []byte) error {
+	return a.fromGob(data, 1)
+}
+
+// fromGob decodes the atom from data found depth levels deep, the root
+// being level 1, so that deeply nested payloads fail before exhausting the stack.
+func (a *Atom) fromGob(data []byte, depth int) error {
+	if depth > DefaultMaxDepth {
+		return fmt.Errorf("atom nesting exceeds max depth %d", DefaultMaxDepth)
+	}
+
 	var temp struct {
 		ID         string
 		Type       string
@@ -32,32 +105,27 @@ func (a *Atom) FromGob(data []byte) error {
 		Children   [][]byte
 	}
 
-	// Register the type
-	gob.Register(&Atom{})
-
 	decoder := gob.NewDecoder(bytes.NewReader(data))
 	if err := decoder.Decode(&temp); err != nil {
 		return fmt.Errorf("error decoding atom from gob: %v", err)
 	}
 
-	a.mu.Lock()
-	defer a.mu.Unlock()
-
-	a.id = temp.ID
-	a.atomType = temp.Type
-	a.properties = temp.Properties
-
 	// Decode children
-	a.children = make([]AtomInterface, len(temp.Children))
+	children := make([]AtomInterface, len(temp.Children))
 	for i, childData := range temp.Children {
 		child := &Atom{}
-		if err := child.FromGob(childData); err != nil {
+		if err := child.fromGob(childData, depth+1); err != nil {
 			return fmt.Errorf("error decoding child %d: %v", i, err)
 		}
-		a.children[i] = child
+		children[i] = child
 	}
 
-	return nil
+	return a.replaceWith(&Atom{
+		id:         temp.ID,
+		atomType:   temp.Type,
+		properties: temp.Properties,
+		children:   children,
+	})
 }
 
 // GobEncode implements the gob.GobEncoder interface.
@@ -102,8 +170,21 @@ func (a *Atom) SetType(atomType string) AtomInterface {
 	return a
 }
 
+// String returns a concise, non-recursive summary of the atom,
+// such as Atom{id=a1, type=page, props=2, children=3}.
+// It implements fmt.Stringer, so atoms print nicely with %v.
+func (a *Atom) String() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	a.childrenMu.RLock()
+	defer a.childrenMu.RUnlock()
+	return fmt.Sprintf("Atom{id=%s, type=%s, props=%d, children=%d}",
+		a.id, a.atomType, len(a.properties), len(a.children))
+}
+
 // WithData adds initial data to the Atom.
 // This is a convenience function for setting multiple key-value pairs at once.
+// Like WithProperties, properties rejected by options set earlier are skipped.
 func WithData(data map[string]string) AtomOption {
 	return func(a *Atom) {
 		a.mu.Lock()
@@ -115,10 +196,14 @@ func WithData(data map[string]string) AtomOption {
 			case "type":
 				a.atomType = v
 			default:
+				key, value, err := a.preparePropertyLocked(k, v)
+				if err != nil {
+					continue
+				}
 				if a.properties == nil {
 					a.properties = make(map[string]string)
 				}
-				a.properties[k] = v
+				a.properties[key] = value
 			}
 		}
 	}
@@ -145,32 +230,153 @@ func (a *Atom) Get(key string) string {
 	return a.properties[key]
 }
 
+// GetWithOk returns the value for the given key and whether the key exists,
+// like a Go map comma-ok lookup. Both are read under a single lock,
+// so the result is consistent even under concurrent mutation.
+func (a *Atom) GetWithOk(key string) (string, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	value, ok := a.properties[key]
+	return value, ok
+}
+
 // Remove removes the value for the given key.
 func (a *Atom) Remove(key string) AtomInterface {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-	delete(a.properties, key)
+	a.mutateProperties(func() {
+		delete(a.properties, key)
+	})
+	return a
+}
+
+// RemoveMatching removes all properties whose key satisfies the predicate,
+// in a single locked operation. It's a no-op if the atom has no properties.
+func (a *Atom) RemoveMatching(pred func(key string) bool) AtomInterface {
+	a.mutateProperties(func() {
+		for key := range a.properties {
+			if pred(key) {
+				delete(a.properties, key)
+			}
+		}
+	})
+	return a
+}
+
+// RemoveAll removes all properties of the atom.
+// It's a no-op if the atom has no properties.
+func (a *Atom) RemoveAll() AtomInterface {
+	a.mutateProperties(func() {
+		clear(a.properties)
+	})
 	return a
 }
 
 // Set sets the value for the given key.
+// If a key validator is set (see WithKeyValidator), the key is normalized
+// first. Writes rejected by the key validator or the value length limit
+// (see WithMaxValueLength) are silently ignored; use SetChecked to get the error.
 func (a *Atom) Set(key, value string) AtomInterface {
-	a.mu.Lock()
-	defer a.mu.Unlock()
+	_ = a.SetChecked(key, value)
+	return a
+}
+
+// SetChecked is like Set, but returns the error of the key validator
+// or the value length limit instead of ignoring rejected writes.
+func (a *Atom) SetChecked(key, value string) error {
+	var err error
+	a.mutateProperties(func() {
+		err = a.setLocked(key, value)
+	})
+	return err
+}
+
+// setLocked sets a property, normalizing the key and recording history.
+// The caller must hold a.mu.
+func (a *Atom) setLocked(key, value string) error {
+	key, value, err := a.preparePropertyLocked(key, value)
+	if err != nil {
+		return err
+	}
 	if a.properties == nil {
 		a.properties = make(map[string]string)
 	}
+	if old, exists := a.properties[key]; exists {
+		a.pushHistory(key, old)
+	}
 	a.properties[key] = value
-	return a
+	return nil
+}
+
+// preparePropertiesLocked applies preparePropertyLocked to every property,
+// returning the prepared copy or the first rejection. The caller must hold a.mu.
+func (a *Atom) preparePropertiesLocked(properties map[string]string) (map[string]string, error) {
+	prepared := make(map[string]string, len(properties))
+	for k, v := range properties {
+		key, value, err := a.preparePropertyLocked(k, v)
+		if err != nil {
+			return nil, err
+		}
+		prepared[key] = value
+	}
+	return prepared, nil
+}
+
+// preparePropertyLocked applies the atom's property options to a key/value
+// pair before it is stored: key validation, value length limit and interning.
+// The caller must hold a.mu.
+func (a *Atom) preparePropertyLocked(key, value string) (string, string, error) {
+	key, err := a.normalizeKeyLocked(key)
+	if err != nil {
+		return "", "", err
+	}
+	value, err = a.checkValueLengthLocked(key, value)
+	if err != nil {
+		return "", "", err
+	}
+	return a.internLocked(key), a.internLocked(value), nil
+}
+
+// normalizeKeyLocked runs the key validator, if any, on key.
+// The caller must hold a.mu.
+func (a *Atom) normalizeKeyLocked(key string) (string, error) {
+	if a.keyValidator == nil {
+		return key, nil
+	}
+	normalized, err := a.keyValidator(key)
+	if err != nil {
+		return "", fmt.Errorf("invalid property key %q: %w", key, err)
+	}
+	return normalized, nil
 }
 
-// GetAll returns all properties of the atom.
+// SetIfAbsent sets the value for the given key only if the key is not
+// already present, and reports whether it wrote the value.
+// The check and the write happen under a single lock, avoiding the
+// race between separate Has and Set calls.
+func (a *Atom) SetIfAbsent(key, value string) bool {
+	written := false
+	a.mutateProperties(func() {
+		key, value, err := a.preparePropertyLocked(key, value)
+		if err != nil {
+			return
+		}
+		if _, exists := a.properties[key]; exists {
+			return
+		}
+		if a.properties == nil {
+			a.properties = make(map[string]string)
+		}
+		a.properties[key] = value
+		written = true
+	})
+	return written
+}
+
+// GetAll returns a copy of all properties of the atom.
+// It always returns a non-nil map, empty if the atom has no properties,
+// like ChildrenGet always returns a slice.
 func (a *Atom) GetAll() map[string]string {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
-	if a.properties == nil {
-		return nil
-	}
 	props := make(map[string]string, len(a.properties))
 	for k, v := range a.properties {
 		props[k] = v
@@ -178,43 +384,228 @@ func (a *Atom) GetAll() map[string]string {
 	return props
 }
 
+// GetAllExcept returns a copy of all properties except those with the given
+// keys, such as volatile timestamps before serialization. Like GetAll, it
+// always returns a non-nil map.
+func (a *Atom) GetAllExcept(keys ...string) map[string]string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	props := make(map[string]string, len(a.properties))
+	for k, v := range a.properties {
+		if !slices.Contains(keys, k) {
+			props[k] = v
+		}
+	}
+	return props
+}
+
+// PropertyCount returns the number of properties, without copying them
+// like len(GetAll()) would. It returns zero if the atom has no properties.
+func (a *Atom) PropertyCount() int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return len(a.properties)
+}
+
+// GetByPrefix returns a copy of the properties whose key starts with prefix,
+// such as all "data-" attributes. It returns an empty map if none match.
+func (a *Atom) GetByPrefix(prefix string) map[string]string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	props := make(map[string]string)
+	for k, v := range a.properties {
+		if strings.HasPrefix(k, prefix) {
+			props[k] = v
+		}
+	}
+	return props
+}
+
 // SetAll sets all properties of the atom.
+// The given map is copied, so later changes to it do not affect the atom.
+// If a key validator or a value length limit is set, every property is
+// checked first, and if any is rejected the properties are left unchanged;
+// use SetAllChecked to get the error.
+// Like WithData, the "id" and "type" keys set the atom's ID and type
+// instead of being stored as properties, so they never shadow those fields.
 func (a *Atom) SetAll(properties map[string]string) AtomInterface {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-	a.properties = properties
+	_ = a.SetAllChecked(properties)
+	return a
+}
+
+// SetAllChecked is like SetAll, but returns the error of the first rejected
+// property instead of ignoring it. On error the atom is left unchanged.
+func (a *Atom) SetAllChecked(properties map[string]string) error {
+	var err error
+	a.mutateProperties(func() {
+		id, atomType := a.id, a.atomType
+		props := make(map[string]string, len(properties))
+		for k, v := range properties {
+			switch k {
+			case "id":
+				id = v
+				continue
+			case "type":
+				atomType = v
+				continue
+			}
+			key, value, prepareErr := a.preparePropertyLocked(k, v)
+			if prepareErr != nil {
+				err = prepareErr
+				return
+			}
+			props[key] = value
+		}
+
+		a.id, a.atomType = id, atomType
+		a.properties = props
+	})
+	return err
+}
+
+// PropertiesGet returns all properties of the atom as Property values,
+// sorted by name so the order is deterministic.
+func (a *Atom) PropertiesGet() []PropertyInterface {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	names := make([]string, 0, len(a.properties))
+	for name := range a.properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]PropertyInterface, 0, len(names))
+	for _, name := range names {
+		result = append(result, NewProperty(name, a.properties[name]))
+	}
+	return result
+}
+
+// PropertiesSet replaces all properties of the atom with the given properties.
+// Nil properties are skipped. If several properties share a name, the last one wins.
+// Like SetAll, if any property is rejected the properties are left unchanged.
+func (a *Atom) PropertiesSet(properties []PropertyInterface) AtomInterface {
+	// Read the properties before locking, as they have locks of their own
+	names := make([]string, 0, len(properties))
+	values := make([]string, 0, len(properties))
+	for _, property := range properties {
+		if property != nil {
+			names = append(names, property.GetName())
+			values = append(values, property.GetValue())
+		}
+	}
+
+	a.mutateProperties(func() {
+		props := make(map[string]string, len(names))
+		for i, name := range names {
+			key, value, err := a.preparePropertyLocked(name, values[i])
+			if err != nil {
+				return
+			}
+			props[key] = value
+		}
+		a.properties = props
+	})
 	return a
 }
 
 // ChildAdd adds a child atom.
 // If child is nil, it's a no-op.
+// With WithUniqueChildIDs, it's also a no-op if a child already has the same ID.
 func (a *Atom) ChildAdd(child AtomInterface) AtomInterface {
 	if child == nil {
 		return a
 	}
-	a.mu.Lock()
-	defer a.mu.Unlock()
-	a.children = append(a.children, child)
+	a.childrenMu.Lock()
+	defer a.childrenMu.Unlock()
+	a.childAddLocked(child)
 	return a
 }
 
-// ChildDeleteByID removes a child atom by its ID.
+// childAddLocked appends a non-nil child, respecting uniqueChildIDs.
+// The caller must hold childrenMu.
+func (a *Atom) childAddLocked(child AtomInterface) {
+	if a.uniqueChildIDs && a.hasChildIDLocked(child.GetID()) {
+		return
+	}
+	a.children = append(a.children, child)
+	a.adopt(child)
+}
+
+// ChildAddUnique adds a child atom only if no immediate child already has
+// the same ID, and reports whether it was added. A nil child is never added.
+func (a *Atom) ChildAddUnique(child AtomInterface) bool {
+	if child == nil {
+		return false
+	}
+	a.childrenMu.Lock()
+	defer a.childrenMu.Unlock()
+	if a.hasChildIDLocked(child.GetID()) {
+		return false
+	}
+	a.children = append(a.children, child)
+	a.adopt(child)
+	return true
+}
+
+// hasChildIDLocked reports whether an immediate child has the given ID.
+// The caller must hold childrenMu.
+func (a *Atom) hasChildIDLocked(id string) bool {
+	for _, child := range a.children {
+		if child != nil && child.GetID() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ChildDeleteByID removes the first immediate child with the given ID.
+// If several children share the ID, only the first one is removed;
+// use ChildDeleteAllByID to remove all of them.
 func (a *Atom) ChildDeleteByID(id string) AtomInterface {
-	a.mu.Lock()
-	defer a.mu.Unlock()
+	a.childrenMu.Lock()
+	defer a.childrenMu.Unlock()
+	a.childDeleteByIDLocked(id)
+	return a
+}
+
+// childDeleteByIDLocked removes the first immediate child with the given ID.
+// The caller must hold childrenMu.
+func (a *Atom) childDeleteByIDLocked(id string) {
 	for i, child := range a.children {
-		if child.GetID() == id {
-			a.children = append(a.children[:i], a.children[i+1:]...)
+		if child != nil && child.GetID() == id {
+			// Build a new slice rather than shifting in place, so slices
+			// previously returned by ChildrenRef are never modified.
+			children := make([]AtomInterface, 0, len(a.children)-1)
+			children = append(children, a.children[:i]...)
+			a.children = append(children, a.children[i+1:]...)
+			a.release(child)
 			break
 		}
 	}
+}
+
+// ChildDeleteAllByID removes all immediate children with the given ID.
+func (a *Atom) ChildDeleteAllByID(id string) AtomInterface {
+	a.childrenMu.Lock()
+	defer a.childrenMu.Unlock()
+	// Build a new slice so slices previously returned by ChildrenRef are never modified
+	children := make([]AtomInterface, 0, len(a.children))
+	for _, child := range a.children {
+		if child != nil && child.GetID() == id {
+			a.release(child)
+			continue
+		}
+		children = append(children, child)
+	}
+	a.children = children
 	return a
 }
 
 // ChildFindByID returns the first immediate child with the given ID, or nil if not found.
 func (a *Atom) ChildFindByID(id string) AtomInterface {
-	a.mu.RLock()
-	defer a.mu.RUnlock()
+	a.childrenMu.RLock()
+	defer a.childrenMu.RUnlock()
 	for _, child := range a.children {
 		if child != nil && child.GetID() == id {
 			return child
@@ -223,53 +614,140 @@ func (a *Atom) ChildFindByID(id string) AtomInterface {
 	return nil
 }
 
+// ChildFindByType returns the first immediate child with the given type, or nil if not found.
+func (a *Atom) ChildFindByType(atomType string) AtomInterface {
+	a.childrenMu.RLock()
+	defer a.childrenMu.RUnlock()
+	for _, child := range a.children {
+		if child != nil && child.GetType() == atomType {
+			return child
+		}
+	}
+	return nil
+}
+
+// ChildIDs returns the IDs of the immediate children in insertion order.
+// Nil children are skipped.
+func (a *Atom) ChildIDs() []string {
+	a.childrenMu.RLock()
+	defer a.childrenMu.RUnlock()
+	ids := make([]string, 0, len(a.children))
+	for _, child := range a.children {
+		if child != nil {
+			ids = append(ids, child.GetID())
+		}
+	}
+	return ids
+}
+
 // ChildrenAdd adds multiple child atoms.
+// Nil children in the input slice will be filtered out, like in ChildrenSet.
+// With WithUniqueChildIDs, children whose ID is already used are skipped.
 func (a *Atom) ChildrenAdd(children []AtomInterface) AtomInterface {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-	a.children = append(a.children, children...)
+	a.childrenMu.Lock()
+	defer a.childrenMu.Unlock()
+	for _, child := range children {
+		if child == nil {
+			continue
+		}
+		if a.uniqueChildIDs && a.hasChildIDLocked(child.GetID()) {
+			continue
+		}
+		a.children = append(a.children, child)
+		a.adopt(child)
+	}
 	return a
 }
 
+// ChildAddAll is a variadic convenience wrapper around ChildrenAdd.
+// Nil children are filtered out.
+func (a *Atom) ChildAddAll(children ...AtomInterface) AtomInterface {
+	return a.ChildrenAdd(children)
+}
+
 // ChildrenGet returns a copy of the children slice.
 func (a *Atom) ChildrenGet() []AtomInterface {
-	a.mu.RLock()
-	defer a.mu.RUnlock()
+	a.childrenMu.RLock()
+	defer a.childrenMu.RUnlock()
 	children := make([]AtomInterface, len(a.children))
 	copy(children, a.children)
 	return children
 }
 
+// ChildrenRef returns the underlying children slice without copying.
+// It is intended for hot read-only paths, such as walking a large tree.
+//
+// The returned slice is a snapshot: later calls to ChildAdd, ChildrenSet or
+// ChildDeleteByID do not modify it. Callers must NOT mutate the returned slice;
+// use ChildrenGet when a modifiable copy is needed.
+func (a *Atom) ChildrenRef() []AtomInterface {
+	a.childrenMu.RLock()
+	defer a.childrenMu.RUnlock()
+	return a.children[:len(a.children):len(a.children)]
+}
+
 // ChildrenLength returns the number of children.
 func (a *Atom) ChildrenLength() int {
-	a.mu.RLock()
-	defer a.mu.RUnlock()
+	a.childrenMu.RLock()
+	defer a.childrenMu.RUnlock()
 	return len(a.children)
 }
 
+// IsLeaf reports whether the atom has no children.
+// Unlike len(ChildrenGet()) == 0, it does not copy the children.
+func (a *Atom) IsLeaf() bool {
+	return a.ChildrenLength() == 0
+}
+
 // ChildrenSet replaces all children with the given slice.
 // Nil children in the input slice will be filtered out.
+// With WithUniqueChildIDs, only the first child with each ID is kept.
 func (a *Atom) ChildrenSet(children []AtomInterface) AtomInterface {
-	a.mu.Lock()
-	defer a.mu.Unlock()
+	a.childrenMu.Lock()
+	defer a.childrenMu.Unlock()
+	a.childrenSetLocked(children)
+	return a
+}
+
+// ChildrenSetChecked is like ChildrenSet, but returns the number of nil
+// entries that were filtered out. Children skipped because of a duplicate
+// ID (see WithUniqueChildIDs) are not counted.
+func (a *Atom) ChildrenSetChecked(children []AtomInterface) (dropped int) {
+	a.childrenMu.Lock()
+	defer a.childrenMu.Unlock()
+	return a.childrenSetLocked(children)
+}
 
-	// Filter out nil children
+// childrenSetLocked replaces the children, filtering out nils and,
+// if uniqueness is enforced, duplicate IDs, and returns the number of
+// nils filtered out. The caller must hold childrenMu.
+func (a *Atom) childrenSetLocked(children []AtomInterface) int {
+	// Filter out nil children, and duplicate IDs if uniqueness is enforced
 	validChildren := make([]AtomInterface, 0, len(children))
+	seen := map[string]bool{}
+	nils := 0
 	for _, child := range children {
-		if child != nil {
-			validChildren = append(validChildren, child)
+		if child == nil {
+			nils++
+			continue
 		}
+		if a.uniqueChildIDs {
+			if seen[child.GetID()] {
+				continue
+			}
+			seen[child.GetID()] = true
+		}
+		validChildren = append(validChildren, child)
 	}
 
-	a.children = make([]AtomInterface, len(validChildren))
-	copy(a.children, validChildren)
-	return a
+	a.setChildrenLocked(validChildren)
+	return nils
 }
 
 // ChildrenFindByType returns all immediate children that match the provided type.
 func (a *Atom) ChildrenFindByType(atomType string) []AtomInterface {
-	a.mu.RLock()
-	defer a.mu.RUnlock()
+	a.childrenMu.RLock()
+	defer a.childrenMu.RUnlock()
 	if len(a.children) == 0 {
 		return []AtomInterface{}
 	}
@@ -282,21 +760,92 @@ func (a *Atom) ChildrenFindByType(atomType string) []AtomInterface {
 	return result
 }
 
+// ChildrenExcludingType returns all immediate children whose type differs
+// from the provided type, preserving their order.
+// It is the complement of ChildrenFindByType.
+func (a *Atom) ChildrenExcludingType(atomType string) []AtomInterface {
+	a.childrenMu.RLock()
+	defer a.childrenMu.RUnlock()
+	result := make([]AtomInterface, 0, len(a.children))
+	for _, child := range a.children {
+		if child != nil && child.GetType() != atomType {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+// ChildrenByTypeMap returns the immediate children grouped by their type,
+// preserving the order of children within each group. Nil children are skipped.
+func (a *Atom) ChildrenByTypeMap() map[string][]AtomInterface {
+	a.childrenMu.RLock()
+	defer a.childrenMu.RUnlock()
+	result := make(map[string][]AtomInterface)
+	for _, child := range a.children {
+		if child != nil {
+			childType := child.GetType()
+			result[childType] = append(result[childType], child)
+		}
+	}
+	return result
+}
+
+// DescendantsByType returns all descendants of the atom (excluding the atom itself)
+// that match the provided type, in pre-order.
+// Unlike ChildrenFindByType it searches at any depth, and unlike FindAtomsByType
+// it never includes the receiver.
+func (a *Atom) DescendantsByType(atomType string) []AtomInterface {
+	result := []AtomInterface{}
+	for _, child := range a.ChildrenGet() {
+		result = append(result, FindAtomsByType(child, atomType)...)
+	}
+	return result
+}
+
 // ToGob encodes the atom to a gob-encoded byte slice.
 // This is the primary method for gob encoding that satisfies the AtomInterface.
 func (a *Atom) ToGob() ([]byte, error) {
+	return a.toGob(true)
+}
+
+// ToGobShallow encodes only the atom's own data (id, type and properties)
+// to a gob-encoded byte slice, with an empty children list.
+// This is useful for persisting node metadata separately from the tree structure.
+// Decode the result with FromGobShallow, FromGob or GobToAtom.
+func (a *Atom) ToGobShallow() ([]byte, error) {
+	return a.toGob(false)
+}
+
+// toGob encodes the atom to gob, recursively encoding
+// its children only if includeChildren is true.
+func (a *Atom) toGob(includeChildren bool) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := a.encodeGob(&buf, includeChildren); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeGob writes the gob encoding of the atom to w, recursively
+// encoding its children only if includeChildren is true.
+func (a *Atom) encodeGob(w io.Writer, includeChildren bool) error {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
+	a.childrenMu.RLock()
+	defer a.childrenMu.RUnlock()
 
 	// First, encode all children to gob
-	childData := make([][]byte, len(a.children))
-	for i, child := range a.children {
-		if child != nil {
-			childBytes, err := child.ToGob()
-			if err != nil {
-				return nil, fmt.Errorf("error encoding child %d: %v", i, err)
+	childData := [][]byte{}
+	if includeChildren {
+		childData = make([][]byte, len(a.children))
+		for i, child := range a.children {
+			if child != nil {
+				childBytes, err := child.ToGob()
+				if err != nil {
+					return fmt.Errorf("error encoding child %d: %v", i, err)
+				}
+				childData[i] = childBytes
 			}
-			childData[i] = childBytes
 		}
 	}
 
@@ -313,28 +862,38 @@ func (a *Atom) ToGob() ([]byte, error) {
 		Children:   childData,
 	}
 
-	var buf bytes.Buffer
-	encoder := gob.NewEncoder(&buf)
-
-	// Register the type
-	gob.Register(&Atom{})
+	encoder := gob.NewEncoder(w)
 
 	// Encode the data
 	if err := encoder.Encode(temp); err != nil {
-		return nil, fmt.Errorf("error encoding atom to gob: %v", err)
+		return fmt.Errorf("error encoding atom to gob: %v", err)
 	}
 
-	return buf.Bytes(), nil
+	return nil
 }
 
 // ToMap converts the atom to a map representation with the following structure:
 // - id: the atom's ID
 // - type: the atom's type
 // - properties: a map containing all properties (excluding id and type)
-// - children: an array of child atoms
+// - children: a []any of child atom maps, each a map[string]any
+//
+// The children use the same types encoding/json produces, so they can be
+// asserted the same way whether the map comes from ToMap or from decoded JSON.
+//
+// Atoms whose type has a mapper registered with RegisterMapper
+// use the mapper's representation instead.
 func (a *Atom) ToMap() map[string]interface{} {
+	// Custom representation registered for this type, looked up
+	// before locking as the mapper reads the atom through its getters
+	if mapper, ok := lookupMapper(a.GetType()); ok {
+		return mapper(a)
+	}
+
 	a.mu.RLock()
 	defer a.mu.RUnlock()
+	a.childrenMu.RLock()
+	defer a.childrenMu.RUnlock()
 
 	// Create a copy of properties excluding id and type
 	props := make(map[string]string, len(a.properties))
@@ -345,7 +904,7 @@ func (a *Atom) ToMap() map[string]interface{} {
 	}
 
 	// Convert children to maps
-	children := make([]map[string]interface{}, 0, len(a.children))
+	children := make([]any, 0, len(a.children))
 	for _, child := range a.children {
 		if child != nil {
 			children = append(children, child.ToMap())
@@ -387,37 +946,113 @@ func (a *Atom) ToJSONPretty() (string, error) {
 	return string(jsonData), nil
 }
 
+// ToMapWithCounts converts the atom to a map like ToMap, additionally
+// injecting a "childCount" key into the map of every atom in the tree.
+// The childCount equals the number of entries in that atom's children.
+func (a *Atom) ToMapWithCounts() map[string]interface{} {
+	result := a.ToMap()
+	addChildCounts(result)
+	return result
+}
+
+// ToJSONWithCounts converts the atom to a JSON string using ToMapWithCounts.
+func (a *Atom) ToJSONWithCounts() (string, error) {
+	data := a.ToMapWithCounts()
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal to JSON: %w", err)
+	}
+	return string(jsonData), nil
+}
+
+// addChildCounts recursively adds a "childCount" key to an atom map
+// and all of its children maps.
+func addChildCounts(atomMap map[string]interface{}) {
+	children, _ := atomMap["children"].([]any)
+	atomMap["childCount"] = len(children)
+	for _, child := range children {
+		if childMap, ok := child.(map[string]any); ok {
+			addChildCounts(childMap)
+		}
+	}
+}
+
 // MemoryUsage returns the estimated memory usage of the atom in bytes,
 // including all its properties and recursively all its children.
 // This is useful for memory profiling and monitoring.
 // Note: This is an approximation and doesn't account for all memory used by the Go runtime.
 func (a *Atom) MemoryUsage() int {
+	return a.MemoryBreakdown().Total
+}
+
+// MemoryBreakdown splits the estimate of MemoryUsage by category,
+// for the atom and recursively all its children.
+type MemoryBreakdown struct {
+	// Properties is the memory used by the property maps, keys and values.
+	Properties int
+	// Children is the memory used by the children slices themselves.
+	Children int
+	// Overhead is the memory used by the Atom structs and the id and type
+	// strings, plus the MemoryUsage of children that are other
+	// AtomInterface implementations, which cannot be broken down.
+	Overhead int
+	// Total is the sum of all categories, equal to MemoryUsage.
+	Total int
+}
+
+// MemoryBreakdown returns the estimated memory usage of the atom and all its
+// children, split into properties, children and overhead.
+func (a *Atom) MemoryBreakdown() MemoryBreakdown {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
+	a.childrenMu.RLock()
+	defer a.childrenMu.RUnlock()
 
-	size := 0
+	var b MemoryBreakdown
 
-	// Approximate size of the properties map structure
-	size += 8                           // map header
-	size += len(a.properties) * (8 + 8) // key and value pointers
-	for k, v := range a.properties {
-		size += len(k) + len(v)
-	}
+	// The Atom struct itself, including the string, map and slice
+	// headers of its fields and both mutexes
+	b.Overhead += int(unsafe.Sizeof(*a))
 
-	// Approximate size of the children slice
-	size += 8 + (len(a.children) * 8) // slice header + interface pointers
+	// Backing bytes of the id and type strings
+	b.Overhead += len(a.id) + len(a.atomType)
 
-	// Approximate size of the mutex (3 int64 values)
-	size += 24
+	// Properties map: runtime header, per-entry overhead and the
+	// string headers plus backing bytes of every key and value
+	if a.properties != nil {
+		b.Properties += mapHeaderSize
+		for k, v := range a.properties {
+			b.Properties += mapEntryOverhead + 2*stringHeaderSize
+			b.Properties += len(k) + len(v)
+		}
+	}
+
+	// Children slice backing array of interface values, plus
+	// recursively the memory used by each child
+	b.Children += cap(a.children) * interfaceSize
+	for _, child := range a.children {
+		if child == nil {
+			continue
+		}
+		if atom, ok := AsAtom(child); ok {
+			childBreakdown := atom.MemoryBreakdown()
+			b.Properties += childBreakdown.Properties
+			b.Children += childBreakdown.Children
+			b.Overhead += childBreakdown.Overhead
+		} else {
+			b.Overhead += child.MemoryUsage()
+		}
+	}
 
-	return size
+	b.Total = b.Properties + b.Children + b.Overhead
+	return b
 }
 
 func (a *Atom) RecursiveFindByID(id string) AtomInterface {
 	if a.GetID() == id {
 		return a
 	}
-	for _, child := range a.children {
+	for _, child := range a.ChildrenGet() {
 		if child != nil {
 			result := child.RecursiveFindByID(id)
 			if result != nil {
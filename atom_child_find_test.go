@@ -69,3 +69,149 @@ func TestChildrenFindByType_NoMatches(t *testing.T) {
 		t.Fatalf("expected 0 matches, got %d", len(got))
 	}
 }
+
+func TestChildIDs_InsertionOrder(t *testing.T) {
+	parent := NewAtom("parent", WithID("p")).(*Atom)
+	parent.ChildAdd(NewAtom("child", WithID("c1")))
+	parent.ChildAdd(NewAtom("child", WithID("c2")))
+	parent.ChildAdd(NewAtom("child", WithID("c3")))
+
+	got := parent.ChildIDs()
+	want := []string{"c1", "c2", "c3"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d ids, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("id %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+}
+
+func TestChildIDs_EmptyIDsAndNoChildren(t *testing.T) {
+	parent := NewAtom("parent", WithID("p")).(*Atom)
+	if got := parent.ChildIDs(); len(got) != 0 {
+		t.Fatalf("expected no ids, got %v", got)
+	}
+
+	child := NewAtom("child", WithID("c1"))
+	child.SetID("")
+	parent.ChildAdd(child)
+	parent.ChildAdd(NewAtom("child", WithID("c2")))
+
+	got := parent.ChildIDs()
+	if len(got) != 2 || got[0] != "" || got[1] != "c2" {
+		t.Fatalf("unexpected ids: %q", got)
+	}
+}
+
+func TestChildrenExcludingType_ExcludesMatchesAndKeepsOrder(t *testing.T) {
+	parent := NewAtom("parent").(*Atom)
+	parent.ChildrenAdd([]AtomInterface{
+		NewAtom("item", WithID("i1")),
+		NewAtom("meta", WithID("m1")),
+		NewAtom("other", WithID("o1")),
+		NewAtom("meta", WithID("m2")),
+		NewAtom("item", WithID("i2")),
+	})
+
+	got := parent.ChildrenExcludingType("meta")
+	want := []string{"i1", "o1", "i2"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d children, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i].GetID() != want[i] {
+			t.Fatalf("child %d: expected %q, got %q", i, want[i], got[i].GetID())
+		}
+	}
+}
+
+func TestChildrenExcludingType_NoChildren(t *testing.T) {
+	parent := NewAtom("parent").(*Atom)
+	if got := parent.ChildrenExcludingType("meta"); len(got) != 0 {
+		t.Fatalf("expected no children, got %d", len(got))
+	}
+}
+
+func TestDescendantsByType_PreOrderExcludingReceiver(t *testing.T) {
+	root := NewAtom("section", WithID("root")).(*Atom)
+	s1 := NewAtom("section", WithID("s1"))
+	s1.ChildAdd(NewAtom("section", WithID("s1a")))
+	s1.ChildAdd(NewAtom("text", WithID("t1")))
+	root.ChildAdd(s1)
+	root.ChildAdd(NewAtom("section", WithID("s2")))
+
+	got := root.DescendantsByType("section")
+	want := []string{"s1", "s1a", "s2"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d descendants, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i].GetID() != want[i] {
+			t.Fatalf("descendant %d: expected %q, got %q", i, want[i], got[i].GetID())
+		}
+	}
+}
+
+func TestDescendantsByType_NoMatches(t *testing.T) {
+	root := NewAtom("section", WithID("root")).(*Atom)
+	root.ChildAdd(NewAtom("text", WithID("t1")))
+	if got := root.DescendantsByType("image"); len(got) != 0 {
+		t.Fatalf("expected no matches, got %d", len(got))
+	}
+}
+
+func TestChildrenByTypeMap_GroupsAndKeepsOrder(t *testing.T) {
+	parent := NewAtom("page").(*Atom)
+	parent.ChildrenAdd([]AtomInterface{
+		NewAtom("header", WithID("h1")),
+		NewAtom("paragraph", WithID("p1")),
+		NewAtom("image", WithID("img1")),
+		NewAtom("paragraph", WithID("p2")),
+	})
+
+	groups := parent.ChildrenByTypeMap()
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %d", len(groups))
+	}
+	paragraphs := groups["paragraph"]
+	if len(paragraphs) != 2 || paragraphs[0].GetID() != "p1" || paragraphs[1].GetID() != "p2" {
+		t.Fatalf("unexpected paragraph group: %v", paragraphs)
+	}
+	if len(groups["header"]) != 1 || len(groups["image"]) != 1 {
+		t.Fatalf("unexpected groups: %v", groups)
+	}
+}
+
+func TestChildrenByTypeMap_NoChildren(t *testing.T) {
+	if groups := NewAtom("page").(*Atom).ChildrenByTypeMap(); len(groups) != 0 {
+		t.Fatalf("expected no groups, got %v", groups)
+	}
+}
+
+func TestChildFindByType_ReturnsFirstImmediateMatch(t *testing.T) {
+	parent := NewAtom("parent").(*Atom)
+	parent.ChildAdd(NewAtom("other", WithID("o1")))
+	parent.ChildAdd(NewAtom("item", WithID("i1")))
+	parent.ChildAdd(NewAtom("item", WithID("i2")))
+
+	got := parent.ChildFindByType("item")
+	if got == nil || got.GetID() != "i1" {
+		t.Fatalf("expected first item child i1, got %+v", got)
+	}
+}
+
+func TestChildFindByType_NotFoundOrEmpty(t *testing.T) {
+	parent := NewAtom("parent").(*Atom)
+	if got := parent.ChildFindByType("item"); got != nil {
+		t.Fatalf("expected nil for empty children, got %+v", got)
+	}
+
+	child := NewAtom("child")
+	child.ChildAdd(NewAtom("item"))
+	parent.ChildAdd(child)
+	if got := parent.ChildFindByType("item"); got != nil {
+		t.Fatalf("expected nil for non-immediate match, got ID=%s", got.GetID())
+	}
+}
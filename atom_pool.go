@@ -0,0 +1,67 @@
+package omni
+
+import "sync"
+
+// AtomPool is a thread-safe pool of reusable atoms, backed by sync.Pool.
+// It reduces allocations when many short-lived atoms are created and discarded,
+// for example while parsing.
+//
+// Atoms returned to the pool with Put are reset and may be handed out again
+// by Get, so a pooled atom must not be retained or used after Put
+// (including through a parent that still references it as a child).
+type AtomPool struct {
+	pool sync.Pool
+}
+
+// NewAtomPool creates a new, empty AtomPool.
+func NewAtomPool() *AtomPool {
+	return &AtomPool{
+		pool: sync.Pool{
+			New: func() any {
+				return &Atom{properties: make(map[string]string)}
+			},
+		},
+	}
+}
+
+// Get returns a reset atom of the given type from the pool,
+// allocating a new one if the pool is empty.
+// Unlike NewAtom, no ID is generated (UID generation would dominate
+// the cost of reuse), so callers should set one with SetID.
+func (p *AtomPool) Get(atomType string) *Atom {
+	atom := p.pool.Get().(*Atom)
+	atom.SetType(atomType)
+	return atom
+}
+
+// Put clears the atom's id, type, properties, children, parent, key watchers
+// and options (such as WithKeyValidator or WithUniqueChildIDs) and returns it
+// to the pool. A nil atom is ignored.
+func (p *AtomPool) Put(atom *Atom) {
+	if atom == nil {
+		return
+	}
+	atom.resetForPool()
+	p.pool.Put(atom)
+}
+
+// resetForPool returns the atom to the state of a new pooled atom with a
+// single assignment, so no field can leak into the next Get. Only the
+// properties map is kept, cleared, for reuse. The children are released, and
+// the children slice is dropped rather than truncated, so slices previously
+// returned by ChildrenRef are never overwritten. The caller must own the atom
+// exclusively, as Put requires.
+func (a *Atom) resetForPool() {
+	for _, child := range a.children {
+		a.release(child)
+	}
+
+	properties := a.properties
+	if properties == nil {
+		properties = make(map[string]string)
+	} else {
+		clear(properties)
+	}
+
+	*a = Atom{properties: properties}
+}
@@ -0,0 +1,115 @@
+package omni
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestAtomPool_GetReturnsResetAtom(t *testing.T) {
+	pool := NewAtomPool()
+
+	a := pool.Get("item")
+	a.SetID("a1")
+	if a.GetType() != "item" || a.GetID() != "a1" {
+		t.Fatalf("unexpected pooled atom: type=%q id=%q", a.GetType(), a.GetID())
+	}
+
+	a.Set("key", "value")
+	a.ChildAdd(NewAtom("child"))
+	pool.Put(a)
+
+	if a.GetID() != "" || a.GetType() != "" || len(a.GetAll()) != 0 || a.ChildrenLength() != 0 {
+		t.Fatalf("expected atom to be cleared by Put, got %v", a)
+	}
+
+	b := pool.Get("other")
+	if b.GetType() != "other" || b.Has("key") || b.ChildrenLength() != 0 {
+		t.Fatalf("expected reset atom from pool, got %v", b)
+	}
+	b.Set("fresh", "1")
+	if b.Get("fresh") != "1" {
+		t.Fatal("expected pooled atom to be usable")
+	}
+}
+
+func TestAtomPool_PutClearsOptions(t *testing.T) {
+	in := NewInterner()
+	a := NewAtom("item",
+		WithInterner(in),
+		WithKeyValidator(func(key string) (string, error) { return "", errors.New("rejected") }),
+		WithMaxValueLength(2, TruncateLongValues),
+		WithUniqueChildIDs(),
+	).(*Atom)
+
+	NewAtomPool().Put(a)
+
+	if err := a.SetChecked("key", "value"); err != nil {
+		t.Fatalf("expected Put to clear the key validator, got %v", err)
+	}
+	if got := a.Get("key"); got != "value" {
+		t.Fatalf("expected Put to clear the value length limit, got %q", got)
+	}
+	if in.Len() != 0 {
+		t.Fatalf("expected Put to clear the interner, got Len() = %d", in.Len())
+	}
+
+	a.ChildAdd(NewAtom("child", WithID("dup")))
+	a.ChildAdd(NewAtom("child", WithID("dup")))
+	if a.ChildrenLength() != 2 {
+		t.Fatal("expected Put to clear WithUniqueChildIDs")
+	}
+}
+
+func TestAtomPool_PutResetsEveryField(t *testing.T) {
+	parent := NewAtom("parent").(*Atom)
+	a := NewAtom("item",
+		WithID("a1"),
+		WithParent(parent),
+		WithPropertyHistory(3),
+		WithInterner(NewInterner()),
+		WithKeyValidator(func(key string) (string, error) { return key, nil }),
+		WithMaxValueLength(10, TruncateLongValues),
+		WithUniqueChildIDs(),
+		WithProperties(map[string]string{"k": "v1"}),
+		WithChildren(NewAtom("child")),
+	).(*Atom)
+	a.Set("k", "v2")
+	a.OnChangeKey("k", func(oldValue, newValue string) {})
+
+	pool := NewAtomPool()
+	pool.Put(a)
+
+	if !reflect.DeepEqual(a, &Atom{properties: map[string]string{}}) {
+		t.Fatalf("expected Put to reset every field, got %+v", a)
+	}
+	if b := pool.Get("item"); !reflect.DeepEqual(b, &Atom{atomType: "item", properties: map[string]string{}}) {
+		t.Fatalf("expected Get to return a reset atom, got %+v", b)
+	}
+}
+
+func TestAtomPool_PutNil(t *testing.T) {
+	NewAtomPool().Put(nil)
+}
+
+func BenchmarkAtom_NewAtomChurn(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		a := NewAtom("item", WithID("id"))
+		a.Set("name", "value")
+		a.Set("kind", "value")
+	}
+}
+
+func BenchmarkAtomPool_Churn(b *testing.B) {
+	pool := NewAtomPool()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a := pool.Get("item")
+		a.SetID("id")
+		a.Set("name", "value")
+		a.Set("kind", "value")
+		pool.Put(a)
+	}
+}
@@ -368,20 +368,42 @@ func TestSetAllAndGetAll_CopySemantics(t *testing.T) {
 	props := map[string]string{"a": "1", "b": "2"}
 	p.SetAll(props)
 
-	// mutate original map after SetAll; atom should now reflect new map reference
+	// mutate original map after SetAll; SetAll copies, so atom must not change
 	props["a"] = "x"
-	if v := p.Get("a"); v != "x" {
-		t.Fatalf("expected atom to reflect SetAll map reference changes; got %q", v)
+	if v := p.Get("a"); v != "1" {
+		t.Fatalf("SetAll must copy; mutation of caller's map leaked into atom: %q", v)
 	}
 
 	// GetAll should return a copy; mutating it should not affect atom
 	got := p.GetAll()
 	got["a"] = "y"
-	if v := p.Get("a"); v != "x" {
+	if v := p.Get("a"); v != "1" {
 		t.Fatalf("GetAll must return copy; mutation leaked into atom: %q", v)
 	}
 }
 
+func TestSetAll_RoutesIDAndType(t *testing.T) {
+	p := NewAtom("parent", WithID("old"))
+	p.SetAll(map[string]string{"id": "new", "type": "section", "title": "Home"})
+
+	if p.GetID() != "new" || p.GetType() != "section" {
+		t.Fatalf("GetID/GetType = %q/%q, want new/section", p.GetID(), p.GetType())
+	}
+	if got := p.GetAll(); len(got) != 1 || got["title"] != "Home" {
+		t.Fatalf("GetAll() = %v, want only title", got)
+	}
+	if p.Has("id") || p.Has("type") {
+		t.Fatal("id and type must not be stored as properties")
+	}
+
+	// A rejected property leaves the id and type unchanged too
+	v := NewAtom("parent", WithID("kept"), WithMaxValueLength(3, RejectLongValues))
+	v.SetAll(map[string]string{"id": "new", "title": "too long"})
+	if v.GetID() != "kept" {
+		t.Fatalf("GetID() = %q after rejected SetAll, want kept", v.GetID())
+	}
+}
+
 func TestHasAndRemove_Behavior(t *testing.T) {
 	p := NewAtom("parent")
 	if p.Has("k") {
@@ -486,3 +508,739 @@ func TestToJSON_WithoutProperties_OmitsPropertiesField(t *testing.T) {
 		t.Fatalf("ToJSON should omit empty properties field: %q", j)
 	}
 }
+
+func BenchmarkAtom_ToGob(b *testing.B) {
+	root := NewAtom("root", WithID("root"))
+	for i := 0; i < 100; i++ {
+		child := NewAtom("item", WithID(fmt.Sprintf("child-%d", i)))
+		child.Set("name", "value")
+		root.ChildAdd(child)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := root.ToGob(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAtom_FromGob(b *testing.B) {
+	root := NewAtom("root", WithID("root"))
+	for i := 0; i < 100; i++ {
+		root.ChildAdd(NewAtom("item", WithID(fmt.Sprintf("child-%d", i))))
+	}
+	data, err := root.ToGob()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := FromGob(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestAtom_PropertyAndChildrenLocksAreIndependent(t *testing.T) {
+	a := NewAtom("test", WithID("locks")).(*Atom)
+
+	// Hold the children lock; property access must not block on it
+	a.childrenMu.Lock()
+	done := make(chan struct{})
+	go func() {
+		a.Set("key", "value")
+		_ = a.Get("key")
+		close(done)
+	}()
+	<-done
+	a.childrenMu.Unlock()
+
+	// Hold the properties lock; children access must not block on it
+	a.mu.Lock()
+	done = make(chan struct{})
+	go func() {
+		a.ChildAdd(NewAtom("child", WithID("c1")))
+		_ = a.ChildrenGet()
+		close(done)
+	}()
+	<-done
+	a.mu.Unlock()
+
+	if a.Get("key") != "value" || a.ChildrenLength() != 1 {
+		t.Fatalf("unexpected state after independent lock access")
+	}
+}
+
+func TestAtom_ChildrenRef_IsSnapshot(t *testing.T) {
+	parent := NewAtom("parent", WithID("p")).(*Atom)
+	parent.ChildAdd(NewAtom("item", WithID("c1")))
+	parent.ChildAdd(NewAtom("item", WithID("c2")))
+	parent.ChildAdd(NewAtom("item", WithID("c3")))
+
+	ref := parent.ChildrenRef()
+	parent.ChildDeleteByID("c1")
+	parent.ChildAdd(NewAtom("item", WithID("c4")))
+
+	if len(ref) != 3 {
+		t.Fatalf("expected snapshot of 3 children, got %d", len(ref))
+	}
+	for i, want := range []string{"c1", "c2", "c3"} {
+		if ref[i].GetID() != want {
+			t.Fatalf("ref[%d] = %q, want %q", i, ref[i].GetID(), want)
+		}
+	}
+	if got := parent.ChildIDs(); len(got) != 3 || got[0] != "c2" || got[2] != "c4" {
+		t.Fatalf("unexpected children after mutation: %v", got)
+	}
+}
+
+func buildBenchmarkTree(depth, width int) AtomInterface {
+	root := NewAtom("node")
+	if depth == 0 {
+		return root
+	}
+	for i := 0; i < width; i++ {
+		root.ChildAdd(buildBenchmarkTree(depth-1, width))
+	}
+	return root
+}
+
+func countWithChildrenGet(a AtomInterface) int {
+	count := 1
+	for _, child := range a.ChildrenGet() {
+		count += countWithChildrenGet(child)
+	}
+	return count
+}
+
+func countWithChildrenRef(a *Atom) int {
+	count := 1
+	for _, child := range a.ChildrenRef() {
+		count += countWithChildrenRef(child.(*Atom))
+	}
+	return count
+}
+
+func BenchmarkAtom_TraverseChildrenGet(b *testing.B) {
+	root := buildBenchmarkTree(4, 8)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		countWithChildrenGet(root)
+	}
+}
+
+func BenchmarkAtom_TraverseChildrenRef(b *testing.B) {
+	root := buildBenchmarkTree(4, 8).(*Atom)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		countWithChildrenRef(root)
+	}
+}
+
+func TestAtom_ToMapWithCounts(t *testing.T) {
+	root := NewAtom("root", WithID("root"))
+	a := NewAtom("node", WithID("a"))
+	a.ChildAdd(NewAtom("leaf", WithID("a1")))
+	a.ChildAdd(NewAtom("leaf", WithID("a2")))
+	root.ChildAdd(a)
+	root.ChildAdd(NewAtom("node", WithID("b")))
+
+	var check func(m map[string]interface{})
+	check = func(m map[string]interface{}) {
+		children := m["children"].([]any)
+		if got := m["childCount"]; got != len(children) {
+			t.Fatalf("atom %v: childCount = %v, want %d", m["id"], got, len(children))
+		}
+		for _, child := range children {
+			check(child.(map[string]any))
+		}
+	}
+
+	m := root.(*Atom).ToMapWithCounts()
+	check(m)
+	if m["childCount"] != 2 {
+		t.Fatalf("expected root childCount 2, got %v", m["childCount"])
+	}
+	leaf := m["children"].([]any)[1].(map[string]any)
+	if leaf["childCount"] != 0 {
+		t.Fatalf("expected leaf childCount 0, got %v", leaf["childCount"])
+	}
+}
+
+func TestAtom_ToJSONWithCounts(t *testing.T) {
+	root := NewAtom("root", WithID("root"))
+	root.ChildAdd(NewAtom("leaf", WithID("l1")))
+
+	j, err := root.(*Atom).ToJSONWithCounts()
+	if err != nil {
+		t.Fatalf("ToJSONWithCounts error: %v", err)
+	}
+	if !strings.Contains(j, `"childCount":1`) || !strings.Contains(j, `"childCount":0`) {
+		t.Fatalf("ToJSONWithCounts missing childCount: %q", j)
+	}
+}
+
+func TestAtom_MemoryUsage_GrowsMonotonically(t *testing.T) {
+	a := NewAtom("test", WithID("mem"))
+	prev := a.MemoryUsage()
+
+	for i := 0; i < 10; i++ {
+		a.Set(fmt.Sprintf("key-%d", i), strings.Repeat("v", i+1))
+		got := a.MemoryUsage()
+		if got <= prev {
+			t.Fatalf("MemoryUsage did not grow after Set #%d: before=%d after=%d", i, prev, got)
+		}
+		prev = got
+	}
+
+	child := NewAtom("child", WithID("c1"))
+	child.Set("payload", strings.Repeat("x", 256))
+	a.ChildAdd(child)
+	got := a.MemoryUsage()
+	if got < prev+child.MemoryUsage() {
+		t.Fatalf("MemoryUsage should include children: parent=%d child=%d", got, child.MemoryUsage())
+	}
+}
+
+func TestAtom_MemoryBreakdown(t *testing.T) {
+	a := NewAtom("test", WithID("mem"), WithProperty("title", "Home")).(*Atom)
+	child := NewAtom("child", WithID("c1"), WithProperty("payload", strings.Repeat("x", 256)))
+	a.ChildAdd(child)
+	a.ChildAdd(customAtom{AtomInterface: NewAtom("custom")})
+
+	b := a.MemoryBreakdown()
+	if b.Total != a.MemoryUsage() {
+		t.Fatalf("Total = %d, want MemoryUsage() = %d", b.Total, a.MemoryUsage())
+	}
+	if b.Total != b.Properties+b.Children+b.Overhead {
+		t.Fatalf("Total = %d, want sum of categories %+v", b.Total, b)
+	}
+	if b.Properties < 256 {
+		t.Fatalf("expected child properties to be included, got %d", b.Properties)
+	}
+	if b.Children < 2*interfaceSize {
+		t.Fatalf("expected the children slice to be counted, got %d", b.Children)
+	}
+
+	before := a.MemoryBreakdown()
+	a.Set("more", strings.Repeat("y", 100))
+	after := a.MemoryBreakdown()
+	if after.Properties <= before.Properties || after.Children != before.Children || after.Overhead != before.Overhead {
+		t.Fatalf("expected only Properties to grow: before=%+v after=%+v", before, after)
+	}
+}
+
+func TestAtom_ToMap_ConcurrentChildMutation(t *testing.T) {
+	parent := NewAtom("parent", WithID("p"))
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	// Mutators
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				id := fmt.Sprintf("c-%d-%d", worker, i)
+				switch i % 4 {
+				case 0:
+					parent.ChildAdd(NewAtom("item", WithID(id)))
+				case 1:
+					parent.ChildrenAdd([]AtomInterface{nil, NewAtom("item", WithID(id))})
+				case 2:
+					parent.ChildDeleteByID(fmt.Sprintf("c-%d-%d", worker, i-2))
+				case 3:
+					parent.ChildrenSet([]AtomInterface{NewAtom("item", WithID(id)), nil})
+				}
+			}
+		}(w)
+	}
+
+	// Reader
+	for i := 0; i < 500; i++ {
+		m := parent.ToMap()
+		children, ok := m["children"].([]any)
+		if !ok {
+			t.Fatalf("children has unexpected type %T", m["children"])
+		}
+		for j, c := range children {
+			child, _ := c.(map[string]any)
+			if child == nil {
+				t.Fatalf("ToMap produced nil child entry at %d", j)
+			}
+			if id, _ := child["id"].(string); id == "" {
+				t.Fatalf("ToMap produced child without id at %d", j)
+			}
+		}
+	}
+
+	close(stop)
+	wg.Wait()
+}
+
+func TestAtom_String(t *testing.T) {
+	a := NewAtom("page", WithID("a1"), WithProperty("title", "Home"), WithProperty("uri", "/"))
+	a.ChildAdd(NewAtom("header"))
+	a.ChildAdd(NewAtom("paragraph"))
+	a.ChildAdd(NewAtom("footer"))
+
+	want := "Atom{id=a1, type=page, props=2, children=3}"
+	if got := a.(*Atom).String(); got != want {
+		t.Fatalf("String() = %q, want %q", got, want)
+	}
+	if got := fmt.Sprintf("%v", a); got != want {
+		t.Fatalf("Sprintf(%%v) = %q, want %q", got, want)
+	}
+}
+
+func TestAtom_ChildrenAdd_FiltersNils(t *testing.T) {
+	parent := NewAtom("parent", WithID("p"))
+	parent.ChildrenAdd([]AtomInterface{
+		nil,
+		NewAtom("item", WithID("c1")),
+		nil,
+		NewAtom("item", WithID("c2")),
+		nil,
+	})
+
+	children := parent.ChildrenGet()
+	if len(children) != 2 {
+		t.Fatalf("expected nils to be dropped, got %d children", len(children))
+	}
+	if children[0].GetID() != "c1" || children[1].GetID() != "c2" {
+		t.Fatalf("unexpected children order: %s, %s", children[0].GetID(), children[1].GetID())
+	}
+}
+
+func TestAtom_ChildAddAll(t *testing.T) {
+	parent := NewAtom("parent", WithID("p")).(*Atom)
+	parent.ChildAddAll(NewAtom("item", WithID("c1")), nil, NewAtom("item", WithID("c2")))
+	parent.ChildAddAll()
+
+	if got := parent.ChildIDs(); len(got) != 2 || got[0] != "c1" || got[1] != "c2" {
+		t.Fatalf("unexpected children: %v", got)
+	}
+}
+
+func TestAtom_MixedNilChildren_LengthGetAndSerializationAgree(t *testing.T) {
+	mixed := []AtomInterface{nil, NewAtom("item", WithID("c1")), nil, NewAtom("item", WithID("c2"))}
+
+	viaAdd := NewAtom("parent", WithID("p1"))
+	viaAdd.ChildrenAdd(mixed)
+	viaOption := NewAtom("parent", WithID("p2"), WithChildren(mixed...))
+
+	for _, parent := range []AtomInterface{viaAdd, viaOption} {
+		if got := parent.ChildrenLength(); got != 2 {
+			t.Fatalf("%s: ChildrenLength() = %d, want 2", parent.GetID(), got)
+		}
+		if got := len(parent.ChildrenGet()); got != 2 {
+			t.Fatalf("%s: len(ChildrenGet()) = %d, want 2", parent.GetID(), got)
+		}
+		if got := len(parent.ToMap()["children"].([]any)); got != 2 {
+			t.Fatalf("%s: ToMap children = %d, want 2", parent.GetID(), got)
+		}
+
+		data, err := parent.ToGob()
+		if err != nil {
+			t.Fatalf("%s: ToGob() error = %v", parent.GetID(), err)
+		}
+		decoded, err := FromGob(data)
+		if err != nil {
+			t.Fatalf("%s: FromGob() error = %v", parent.GetID(), err)
+		}
+		if got := decoded.ChildrenLength(); got != 2 {
+			t.Fatalf("%s: decoded ChildrenLength() = %d, want 2", parent.GetID(), got)
+		}
+	}
+}
+
+func TestAtom_GetWithOk(t *testing.T) {
+	a := NewAtom("test").(*Atom)
+	a.Set("empty", "")
+	a.Set("name", "Alice")
+
+	if v, ok := a.GetWithOk("name"); !ok || v != "Alice" {
+		t.Fatalf("GetWithOk(name) = %q, %v", v, ok)
+	}
+	if v, ok := a.GetWithOk("empty"); !ok || v != "" {
+		t.Fatalf("GetWithOk(empty) = %q, %v; want \"\", true", v, ok)
+	}
+	if v, ok := a.GetWithOk("missing"); ok || v != "" {
+		t.Fatalf("GetWithOk(missing) = %q, %v; want \"\", false", v, ok)
+	}
+
+	var nilProps Atom
+	if _, ok := nilProps.GetWithOk("any"); ok {
+		t.Fatal("GetWithOk should report false when properties are nil")
+	}
+}
+
+func TestAtom_RemoveMatching(t *testing.T) {
+	a := NewAtom("test", WithProperty("_internal", "1"), WithProperty("_rev", "2"), WithProperty("name", "Alice")).(*Atom)
+
+	a.RemoveMatching(func(key string) bool { return strings.HasPrefix(key, "_") })
+
+	if got := a.GetAll(); len(got) != 1 || got["name"] != "Alice" {
+		t.Fatalf("unexpected properties after RemoveMatching: %v", got)
+	}
+}
+
+func TestAtom_GetAll_NeverNil(t *testing.T) {
+	var empty Atom
+	if got := empty.GetAll(); got == nil || len(got) != 0 {
+		t.Fatalf("GetAll() on nil properties = %#v, want empty non-nil map", got)
+	}
+
+	a := NewAtom("test").(*Atom)
+	a.RemoveAll()
+	if got := a.GetAll(); got == nil {
+		t.Fatal("GetAll() = nil, want empty non-nil map")
+	}
+}
+
+func TestAtom_GetAllExcept(t *testing.T) {
+	a := NewAtom("test", WithProperties(map[string]string{
+		"title": "Home", "updated_at": "now", "lang": "en",
+	})).(*Atom)
+
+	got := a.GetAllExcept("updated_at", "missing")
+	if len(got) != 2 || got["title"] != "Home" || got["lang"] != "en" {
+		t.Fatalf("GetAllExcept() = %v, want title and lang", got)
+	}
+
+	got["title"] = "changed"
+	if a.Get("title") != "Home" || !a.Has("updated_at") {
+		t.Fatal("GetAllExcept must return a copy and leave the atom unchanged")
+	}
+
+	if all := a.GetAllExcept(); len(all) != 3 {
+		t.Fatalf("GetAllExcept() with no keys = %v, want all properties", all)
+	}
+
+	var empty Atom
+	if got := empty.GetAllExcept("title"); got == nil || len(got) != 0 {
+		t.Fatalf("GetAllExcept() on nil properties = %#v, want empty non-nil map", got)
+	}
+}
+
+func TestAtom_PropertyCount(t *testing.T) {
+	a := NewAtom("test", WithProperty("a", "1"), WithProperty("b", "2")).(*Atom)
+	if got := a.PropertyCount(); got != 2 {
+		t.Fatalf("PropertyCount() = %d, want 2", got)
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { a.PropertyCount() }); allocs != 0 {
+		t.Fatalf("PropertyCount() allocated %v times, want 0", allocs)
+	}
+
+	var empty Atom
+	if got := empty.PropertyCount(); got != 0 {
+		t.Fatalf("PropertyCount() on nil properties = %d, want 0", got)
+	}
+}
+
+func TestAtom_GetByPrefix(t *testing.T) {
+	a := NewAtom("div", WithProperties(map[string]string{
+		"data-foo":   "1",
+		"data-bar":   "2",
+		"aria-label": "Menu",
+		"class":      "nav",
+	})).(*Atom)
+
+	got := a.GetByPrefix("data-")
+	if len(got) != 2 || got["data-foo"] != "1" || got["data-bar"] != "2" {
+		t.Fatalf("GetByPrefix(data-) = %v", got)
+	}
+
+	// The result is a copy
+	got["data-foo"] = "changed"
+	if a.Get("data-foo") != "1" {
+		t.Fatal("expected GetByPrefix to return a copy")
+	}
+
+	if none := a.GetByPrefix("x-"); none == nil || len(none) != 0 {
+		t.Fatalf("GetByPrefix(x-) = %v, want empty non-nil map", none)
+	}
+
+	var empty Atom
+	if none := empty.GetByPrefix(""); none == nil || len(none) != 0 {
+		t.Fatalf("GetByPrefix on nil properties = %v, want empty non-nil map", none)
+	}
+}
+
+func TestAtom_RemoveAll(t *testing.T) {
+	a := NewAtom("test", WithProperty("a", "1"), WithProperty("b", "2")).(*Atom)
+	a.RemoveAll()
+	if got := a.GetAll(); len(got) != 0 {
+		t.Fatalf("expected no properties after RemoveAll, got %v", got)
+	}
+
+	// No-ops when properties is nil
+	var empty Atom
+	empty.RemoveAll()
+	empty.RemoveMatching(func(string) bool { return true })
+}
+
+func TestAtom_SetIfAbsent(t *testing.T) {
+	a := NewAtom("test").(*Atom)
+
+	if !a.SetIfAbsent("lang", "en") {
+		t.Fatal("expected SetIfAbsent to write an absent key")
+	}
+	if a.SetIfAbsent("lang", "fr") {
+		t.Fatal("expected SetIfAbsent not to overwrite an existing key")
+	}
+	if a.Get("lang") != "en" {
+		t.Fatalf("expected lang to stay en, got %q", a.Get("lang"))
+	}
+}
+
+func TestAtom_SetIfAbsent_ConcurrentSingleWinner(t *testing.T) {
+	a := NewAtom("test").(*Atom)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	winners := 0
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if a.SetIfAbsent("key", fmt.Sprintf("v%d", i)) {
+				mu.Lock()
+				winners++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if winners != 1 {
+		t.Fatalf("expected exactly one winner, got %d", winners)
+	}
+}
+
+func TestAtom_ChildAddUnique(t *testing.T) {
+	parent := NewAtom("parent").(*Atom)
+
+	if !parent.ChildAddUnique(NewAtom("item", WithID("c1"))) {
+		t.Fatal("expected first child to be added")
+	}
+	if parent.ChildAddUnique(NewAtom("item", WithID("c1"))) {
+		t.Fatal("expected duplicate id not to be added")
+	}
+	if parent.ChildAddUnique(nil) {
+		t.Fatal("expected nil child not to be added")
+	}
+	if parent.ChildrenLength() != 1 {
+		t.Fatalf("expected 1 child, got %d", parent.ChildrenLength())
+	}
+
+	// ChildAdd without the option still allows duplicates
+	parent.ChildAdd(NewAtom("item", WithID("c1")))
+	if parent.ChildrenLength() != 2 {
+		t.Fatalf("expected ChildAdd to allow duplicates by default, got %d", parent.ChildrenLength())
+	}
+}
+
+func TestAtom_WithUniqueChildIDs(t *testing.T) {
+	parent := NewAtom("parent",
+		WithUniqueChildIDs(),
+		WithChildren(NewAtom("item", WithID("c1")), NewAtom("item", WithID("c1"))),
+	).(*Atom)
+
+	parent.ChildAdd(NewAtom("item", WithID("c1")))
+	parent.ChildrenAdd([]AtomInterface{NewAtom("item", WithID("c2")), NewAtom("item", WithID("c2"))})
+	parent.ChildAddAll(NewAtom("item", WithID("c3")), NewAtom("item", WithID("c1")))
+
+	if got := parent.ChildIDs(); strings.Join(got, ",") != "c1,c2,c3" {
+		t.Fatalf("unexpected children: %v", got)
+	}
+
+	parent.ChildrenSet([]AtomInterface{NewAtom("item", WithID("x")), NewAtom("other", WithID("x"))})
+	children := parent.ChildrenGet()
+	if len(children) != 1 || children[0].GetType() != "item" {
+		t.Fatalf("expected ChildrenSet to keep the first child per id, got %v", children)
+	}
+}
+
+func TestAtom_ChildDeleteByID_RemovesFirstDuplicateOnly(t *testing.T) {
+	parent := NewAtom("parent").(*Atom)
+	parent.ChildAdd(NewAtom("first", WithID("dup")))
+	parent.ChildAdd(NewAtom("item", WithID("keep")))
+	parent.ChildAdd(NewAtom("second", WithID("dup")))
+
+	parent.ChildDeleteByID("dup")
+
+	children := parent.ChildrenGet()
+	if len(children) != 2 || children[0].GetID() != "keep" || children[1].GetType() != "second" {
+		t.Fatalf("expected only the first duplicate to be removed, got %v", children)
+	}
+}
+
+func TestAtom_ChildDeleteAllByID(t *testing.T) {
+	parent := NewAtom("parent").(*Atom)
+	parent.ChildAdd(NewAtom("item", WithID("dup")))
+	parent.ChildAdd(NewAtom("item", WithID("keep")))
+	parent.ChildAdd(NewAtom("item", WithID("dup")))
+	parent.ChildAdd(NewAtom("item", WithID("dup")))
+
+	parent.ChildDeleteAllByID("dup")
+
+	if got := parent.ChildIDs(); len(got) != 1 || got[0] != "keep" {
+		t.Fatalf("expected all duplicates to be removed, got %v", got)
+	}
+
+	parent.ChildDeleteAllByID("missing")
+	if parent.ChildrenLength() != 1 {
+		t.Fatalf("expected no-op for missing id, got %d children", parent.ChildrenLength())
+	}
+}
+
+func TestAtom_WithKeyValidator(t *testing.T) {
+	validator := func(key string) (string, error) {
+		if key == "" || strings.ContainsAny(key, " -") {
+			return "", fmt.Errorf("not an identifier")
+		}
+		return strings.ToLower(key), nil
+	}
+
+	a := NewAtom("test",
+		WithKeyValidator(validator),
+		WithProperties(map[string]string{"Title": "Home", "bad key": "x"}),
+	).(*Atom)
+
+	if got := a.GetAll(); len(got) != 1 || got["title"] != "Home" {
+		t.Fatalf("expected initial properties to be validated, got %v", got)
+	}
+
+	if err := a.SetChecked("Name", "Alice"); err != nil {
+		t.Fatalf("SetChecked() error = %v", err)
+	}
+	if a.Get("name") != "Alice" || a.Has("Name") {
+		t.Fatal("expected SetChecked to store the normalized key")
+	}
+
+	err := a.SetChecked("bad-key", "x")
+	if err == nil || !strings.Contains(err.Error(), `invalid property key "bad-key"`) {
+		t.Fatalf("SetChecked() error = %v, want invalid key error", err)
+	}
+
+	a.Set("other key", "x")
+	if a.Has("other key") {
+		t.Fatal("expected Set to ignore rejected keys")
+	}
+
+	a.SetAll(map[string]string{"A": "1", "bad key": "2"})
+	if got := a.GetAll(); len(got) != 2 {
+		t.Fatalf("expected SetAll with a rejected key to leave properties unchanged, got %v", got)
+	}
+	a.SetAll(map[string]string{"A": "1", "B": "2"})
+	if got := a.GetAll(); len(got) != 2 || got["a"] != "1" || got["b"] != "2" {
+		t.Fatalf("expected SetAll to normalize keys, got %v", got)
+	}
+}
+
+func TestAtom_WithKeyValidator_AllWritePaths(t *testing.T) {
+	validator := func(key string) (string, error) {
+		if strings.Contains(key, " ") {
+			return "", fmt.Errorf("contains a space")
+		}
+		return strings.ToLower(key), nil
+	}
+
+	a := NewAtom("test",
+		WithKeyValidator(validator),
+		WithData(map[string]string{"Title": "Home", "bad key": "x"}),
+	).(*Atom)
+	if got := a.GetAll(); len(got) != 1 || got["title"] != "Home" {
+		t.Fatalf("expected WithData to validate keys, got %v", got)
+	}
+
+	if a.SetIfAbsent("Bad Key", "x") || a.Has("Bad Key") {
+		t.Fatal("expected SetIfAbsent to reject invalid keys")
+	}
+	if a.SetIfAbsent("TITLE", "Other") || a.Get("title") != "Home" {
+		t.Fatal("expected SetIfAbsent to check the normalized key")
+	}
+	if !a.SetIfAbsent("Lang", "en") || a.Get("lang") != "en" {
+		t.Fatal("expected SetIfAbsent to store the normalized key")
+	}
+
+	a.PropertiesSet([]PropertyInterface{NewProperty("A", "1"), NewProperty("bad key", "2")})
+	if got := a.GetAll(); len(got) != 2 || got["title"] != "Home" {
+		t.Fatalf("expected PropertiesSet with a rejected key to leave properties unchanged, got %v", got)
+	}
+	a.PropertiesSet([]PropertyInterface{NewProperty("A", "1")})
+	if got := a.GetAll(); len(got) != 1 || got["a"] != "1" {
+		t.Fatalf("expected PropertiesSet to normalize keys, got %v", got)
+	}
+}
+
+func TestAtom_SetAllChecked(t *testing.T) {
+	a := NewAtom("test",
+		WithKeyValidator(func(key string) (string, error) {
+			if key == "bad" {
+				return "", fmt.Errorf("reserved")
+			}
+			return key, nil
+		}),
+		WithProperty("keep", "1"),
+	).(*Atom)
+
+	err := a.SetAllChecked(map[string]string{"ok": "1", "bad": "2"})
+	if err == nil || !strings.Contains(err.Error(), `invalid property key "bad"`) {
+		t.Fatalf("SetAllChecked() error = %v, want invalid key error", err)
+	}
+	if got := a.GetAll(); len(got) != 1 || got["keep"] != "1" {
+		t.Fatalf("expected a rejected SetAllChecked to leave properties unchanged, got %v", got)
+	}
+
+	if err := a.SetAllChecked(map[string]string{"ok": "1"}); err != nil {
+		t.Fatalf("SetAllChecked() error = %v", err)
+	}
+	if got := a.GetAll(); len(got) != 1 || got["ok"] != "1" {
+		t.Fatalf("GetAll() = %v, want only ok", got)
+	}
+}
+
+func TestAtom_SetChecked_WithoutValidator(t *testing.T) {
+	a := NewAtom("test").(*Atom)
+	if err := a.SetChecked("Any Key", "v"); err != nil {
+		t.Fatalf("SetChecked() error = %v", err)
+	}
+	if a.Get("Any Key") != "v" {
+		t.Fatal("expected keys to be stored as is without a validator")
+	}
+}
+
+func TestAtom_ChildrenSetChecked(t *testing.T) {
+	a := NewAtom("parent", WithUniqueChildIDs()).(*Atom)
+	first := NewAtom("item", WithID("a"))
+
+	dropped := a.ChildrenSetChecked([]AtomInterface{nil, first, nil, NewAtom("item", WithID("a")), NewAtom("item", WithID("b"))})
+
+	if dropped != 2 {
+		t.Fatalf("ChildrenSetChecked() = %d, want 2 nils dropped", dropped)
+	}
+	if ids := a.ChildIDs(); len(ids) != 2 || ids[0] != "a" || ids[1] != "b" {
+		t.Fatalf("unexpected children: %v", ids)
+	}
+	if a.ChildrenGet()[0] != first {
+		t.Fatal("expected the first child with a duplicate ID to be kept")
+	}
+
+	if dropped := a.ChildrenSetChecked(nil); dropped != 0 || a.ChildrenLength() != 0 {
+		t.Fatalf("ChildrenSetChecked(nil) = %d, children = %d", dropped, a.ChildrenLength())
+	}
+}
@@ -0,0 +1,72 @@
+package omni
+
+// Builder is a fluent API for declaratively assembling atom trees.
+//
+// Example:
+//
+//	page := omni.NewBuilder().
+//		Type("page").
+//		ID("home").
+//		Prop("title", "Home").
+//		Child(func(b *omni.Builder) {
+//			b.Type("header").Prop("text", "Welcome")
+//		}).
+//		Build()
+type Builder struct {
+	atomType   string
+	id         string
+	properties map[string]string
+	children   []*Builder
+}
+
+// NewBuilder creates a new, empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{properties: make(map[string]string)}
+}
+
+// Type sets the type of the atom being built.
+func (b *Builder) Type(atomType string) *Builder {
+	b.atomType = atomType
+	return b
+}
+
+// ID sets the ID of the atom being built.
+// If no ID is set, one is generated by NewAtom.
+func (b *Builder) ID(id string) *Builder {
+	b.id = id
+	return b
+}
+
+// Prop sets a property of the atom being built.
+// Like WithProperties, it will not set 'id' or 'type'.
+func (b *Builder) Prop(key, value string) *Builder {
+	b.properties[key] = value
+	return b
+}
+
+// Child builds a subtree with the given function and attaches it
+// as the next child of the atom being built.
+func (b *Builder) Child(fn func(*Builder)) *Builder {
+	child := NewBuilder()
+	fn(child)
+	b.children = append(b.children, child)
+	return b
+}
+
+// Build creates the atom, recursively building and attaching its children in order.
+func (b *Builder) Build() AtomInterface {
+	children := make([]AtomInterface, 0, len(b.children))
+	for _, child := range b.children {
+		children = append(children, child.Build())
+	}
+
+	opts := []AtomOption{
+		WithProperties(b.properties),
+		WithChildren(children...),
+	}
+	if b.id != "" {
+		opts = append(opts, WithID(b.id))
+	}
+
+	return NewAtom(b.atomType, opts...)
+}
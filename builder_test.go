@@ -0,0 +1,47 @@
+package omni
+
+import "testing"
+
+func TestBuilder_BuildsNestedTree(t *testing.T) {
+	root := NewBuilder().
+		Type("page").
+		ID("home").
+		Prop("title", "Home").
+		Child(func(b *Builder) {
+			b.Type("header").ID("h1").Prop("text", "Welcome")
+		}).
+		Child(func(b *Builder) {
+			b.Type("list").ID("l1").
+				Child(func(b *Builder) { b.Type("item").ID("i1") }).
+				Child(func(b *Builder) { b.Type("item").ID("i2") })
+		}).
+		Build()
+
+	if root.GetID() != "home" || root.GetType() != "page" || root.Get("title") != "Home" {
+		t.Fatalf("unexpected root: %v", root)
+	}
+
+	children := root.ChildrenGet()
+	if len(children) != 2 || children[0].GetID() != "h1" || children[1].GetID() != "l1" {
+		t.Fatalf("unexpected children: %v", children)
+	}
+	if children[0].Get("text") != "Welcome" {
+		t.Fatalf("unexpected header text: %q", children[0].Get("text"))
+	}
+
+	items := children[1].ChildrenGet()
+	if len(items) != 2 || items[0].GetID() != "i1" || items[1].GetID() != "i2" {
+		t.Fatalf("unexpected list items: %v", items)
+	}
+}
+
+func TestBuilder_GeneratesIDAndIgnoresReservedProps(t *testing.T) {
+	atom := NewBuilder().Type("item").Prop("id", "x").Prop("type", "y").Build()
+
+	if atom.GetID() == "" || atom.GetID() == "x" {
+		t.Fatalf("expected generated ID, got %q", atom.GetID())
+	}
+	if atom.GetType() != "item" || atom.Has("id") || atom.Has("type") {
+		t.Fatalf("unexpected atom: %v", atom)
+	}
+}
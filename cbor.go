@@ -0,0 +1,305 @@
+package omni
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// CBOR (RFC 8949) major types used by ToCBOR and FromCBOR.
+const (
+	cborUnsigned = 0
+	cborNegative = 1
+	cborBytes    = 2
+	cborText     = 3
+	cborArray    = 4
+	cborMap      = 5
+	cborTag      = 6
+	cborSimple   = 7
+)
+
+// cborMaxNesting bounds how deeply FromCBOR descends into nested items.
+// Each atom level uses two items (its map and its children array).
+const cborMaxNesting = 2*DefaultMaxDepth + 2
+
+// ToCBOR encodes the atom as CBOR (RFC 8949), using the same shape as ToJSON:
+// a map with "id", "type", "properties" (omitted if empty) and "children".
+// CBOR is more compact than JSON and, unlike gob, self-describing, so it can be
+// decoded by any CBOR library. The encoding is deterministic: map keys are
+// sorted as RFC 8949 recommends, so equal atoms encode to the same bytes.
+// Custom representations registered with RegisterMapper are not used.
+func (a *Atom) ToCBOR() ([]byte, error) {
+	return appendCBORAtom(nil, a), nil
+}
+
+// FromCBOR decodes an atom encoded by ToCBOR, or any CBOR map with the same
+// shape, into an AtomInterface.
+//
+// Business logic:
+// - Decodes the CBOR data item, which must be the only one in data
+// - Converts it to an atom with MapToAtom, following the same rules as JSON
+// - Numbers, booleans and byte strings are accepted as property values
+// - Indefinite-length items are not supported and return an error
+//
+// Parameters:
+//   - data: the CBOR-encoded atom
+//
+// Returns:
+//   - AtomInterface: the decoded atom
+//   - error: if the data is not valid CBOR or not a valid atom
+func FromCBOR(data []byte) (AtomInterface, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty CBOR data")
+	}
+
+	d := &cborDecoder{data: data}
+	value, err := d.readValue(0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CBOR data: %w", err)
+	}
+	if d.pos != len(data) {
+		return nil, fmt.Errorf("invalid CBOR data: %d trailing bytes", len(data)-d.pos)
+	}
+
+	atomMap, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid CBOR data: expected a map, got %T", value)
+	}
+	return MapToAtom(atomMap)
+}
+
+// appendCBORAtom appends the CBOR encoding of atom to buf.
+func appendCBORAtom(buf []byte, atom AtomInterface) []byte {
+	props := atom.GetAll()
+	delete(props, "id")
+	delete(props, "type")
+
+	children := atom.ChildrenGet()
+	count := 0
+	for _, child := range children {
+		if !isNilAtom(child) {
+			count++
+		}
+	}
+
+	// Keys in deterministic order: shorter keys first, then bytewise
+	fields := 3
+	if len(props) > 0 {
+		fields++
+	}
+	buf = appendCBORHeader(buf, cborMap, uint64(fields))
+	buf = appendCBORText(appendCBORText(buf, "id"), atom.GetID())
+	buf = appendCBORText(appendCBORText(buf, "type"), atom.GetType())
+	buf = appendCBORText(buf, "children")
+	buf = appendCBORHeader(buf, cborArray, uint64(count))
+	for _, child := range children {
+		if !isNilAtom(child) {
+			buf = appendCBORAtom(buf, child)
+		}
+	}
+
+	if len(props) > 0 {
+		keys := make([]string, 0, len(props))
+		for k := range props {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if len(keys[i]) != len(keys[j]) {
+				return len(keys[i]) < len(keys[j])
+			}
+			return keys[i] < keys[j]
+		})
+
+		buf = appendCBORText(buf, "properties")
+		buf = appendCBORHeader(buf, cborMap, uint64(len(keys)))
+		for _, k := range keys {
+			buf = appendCBORText(appendCBORText(buf, k), props[k])
+		}
+	}
+
+	return buf
+}
+
+// appendCBORText appends a CBOR text string.
+func appendCBORText(buf []byte, s string) []byte {
+	buf = appendCBORHeader(buf, cborText, uint64(len(s)))
+	return append(buf, s...)
+}
+
+// appendCBORHeader appends the initial byte, and any following argument
+// bytes, of an item with the given major type and argument.
+func appendCBORHeader(buf []byte, major byte, n uint64) []byte {
+	major <<= 5
+	switch {
+	case n < 24:
+		return append(buf, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(buf, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buf, major|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, major|27), n)
+	}
+}
+
+// cborDecoder decodes generic CBOR data items into Go values:
+// uint64, int64, float64, bool, nil, string, []any and map[string]any.
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+// readValue decodes the next data item, nested depth levels deep.
+func (d *cborDecoder) readValue(depth int) (any, error) {
+	if depth > cborMaxNesting {
+		return nil, fmt.Errorf("items nested more than %d levels deep", cborMaxNesting)
+	}
+
+	major, info, n, err := d.readHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cborUnsigned:
+		return n, nil
+	case cborNegative:
+		if n > math.MaxInt64 {
+			return nil, errors.New("negative integer out of range")
+		}
+		return -1 - int64(n), nil
+	case cborBytes, cborText:
+		b, err := d.readBytes(n)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case cborArray:
+		if n > uint64(len(d.data)-d.pos) {
+			return nil, errors.New("array length exceeds data size")
+		}
+		items := make([]any, 0, n)
+		for i := uint64(0); i < n; i++ {
+			item, err := d.readValue(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case cborMap:
+		if n > uint64(len(d.data)-d.pos) {
+			return nil, errors.New("map length exceeds data size")
+		}
+		m := make(map[string]any, n)
+		for i := uint64(0); i < n; i++ {
+			key, err := d.readValue(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			k, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("map key must be a string, got %T", key)
+			}
+			value, err := d.readValue(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = value
+		}
+		return m, nil
+	case cborTag:
+		// Tags only annotate the item that follows, which is decoded as is
+		return d.readValue(depth + 1)
+	default:
+		return d.readSimple(info, n)
+	}
+}
+
+// readSimple decodes a major type 7 item: booleans, null, undefined and floats.
+func (d *cborDecoder) readSimple(info byte, n uint64) (any, error) {
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 25:
+		return halfToFloat64(uint16(n)), nil
+	case 26:
+		return float64(math.Float32frombits(uint32(n))), nil
+	case 27:
+		return math.Float64frombits(n), nil
+	default:
+		return nil, fmt.Errorf("unsupported simple value %d", info)
+	}
+}
+
+// readHeader decodes the initial byte of an item and its argument.
+func (d *cborDecoder) readHeader() (major, info byte, n uint64, err error) {
+	if d.pos >= len(d.data) {
+		return 0, 0, 0, errors.New("unexpected end of data")
+	}
+	initial := d.data[d.pos]
+	d.pos++
+	major, info = initial>>5, initial&0x1f
+
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info <= 27:
+		size := 1 << (info - 24)
+		b, err := d.readBytes(uint64(size))
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		for _, c := range b {
+			n = n<<8 | uint64(c)
+		}
+		return major, info, n, nil
+	case info == 31:
+		return 0, 0, 0, errors.New("indefinite-length items are not supported")
+	default:
+		return 0, 0, 0, fmt.Errorf("invalid additional information %d", info)
+	}
+}
+
+// readBytes returns the next n bytes of data.
+func (d *cborDecoder) readBytes(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errors.New("unexpected end of data")
+	}
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+// halfToFloat64 converts an IEEE 754 half-precision float to a float64.
+func halfToFloat64(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+
+	var value float64
+	switch exp {
+	case 0:
+		value = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			value = math.Inf(1)
+		} else {
+			value = math.NaN()
+		}
+	default:
+		value = math.Ldexp(mant+1024, exp-25)
+	}
+
+	if h&0x8000 != 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,86 @@
+package omni
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestAtom_CBOR_RoundTrip(t *testing.T) {
+	root := NewAtom("page", WithID("p1"), WithProperties(map[string]string{
+		"title": "Home",
+		"long":  strings.Repeat("x", 70000),
+		"":      "empty key",
+	})).(*Atom)
+	section := NewAtom("section", WithID("s1"))
+	section.ChildAdd(NewAtom("text", WithID("t1"), WithProperty("content", "héllo")))
+	root.ChildAdd(section)
+	root.ChildAdd(NewAtom("footer", WithID("f1")))
+
+	data, err := root.ToCBOR()
+	if err != nil {
+		t.Fatalf("ToCBOR() error = %v", err)
+	}
+
+	decoded, err := FromCBOR(data)
+	if err != nil {
+		t.Fatalf("FromCBOR() error = %v", err)
+	}
+	if diff := ExplainDiff(root, decoded); diff != "" {
+		t.Fatalf("round trip changed the atom: %s", diff)
+	}
+
+	again, _ := decoded.(*Atom).ToCBOR()
+	if !bytes.Equal(data, again) {
+		t.Fatal("expected the encoding to be deterministic")
+	}
+}
+
+func TestAtom_ToCBOR_Encoding(t *testing.T) {
+	atom := NewAtom("t", WithID("a"), WithProperty("k", "v")).(*Atom)
+
+	data, _ := atom.ToCBOR()
+
+	// {"id": "a", "type": "t", "children": [], "properties": {"k": "v"}}
+	want := "a4" + "626964" + "6161" + "6474797065" + "6174" +
+		"686368696c6472656e" + "80" + "6a70726f70657274696573" + "a1" + "616b" + "6176"
+	if got := hex.EncodeToString(data); got != want {
+		t.Fatalf("ToCBOR() = %s, want %s", got, want)
+	}
+}
+
+func TestFromCBOR_ForeignValues(t *testing.T) {
+	// {"id": 7, "type": "item", "properties": {"n": -2, "ok": true, "f": 1.5 (half)}}
+	data, _ := hex.DecodeString("a3" + "626964" + "07" + "6474797065" + "646974656d" +
+		"6a70726f70657274696573" + "a3" + "616e" + "21" + "626f6b" + "f5" + "6166" + "f93e00")
+
+	atom, err := FromCBOR(data)
+	if err != nil {
+		t.Fatalf("FromCBOR() error = %v", err)
+	}
+	if atom.GetID() != "7" || atom.Get("n") != "-2" || atom.Get("ok") != "true" || atom.Get("f") != "1.5" {
+		t.Fatalf("unexpected atom: %v %v", atom, atom.GetAll())
+	}
+}
+
+func TestFromCBOR_Errors(t *testing.T) {
+	tests := map[string]string{
+		"":             "empty",
+		"a1":           "exceeds data size",
+		"bf":           "indefinite-length",
+		"a1616101":     "missing required 'type'",
+		"80":           "expected a map",
+		"a0ff":         "trailing bytes",
+		"a10161":       "map key must be a string",
+		"9bffffffffff": "unexpected end",
+	}
+
+	for input, want := range tests {
+		data, _ := hex.DecodeString(input)
+		_, err := FromCBOR(data)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("FromCBOR(%s) error = %v, want %q", input, err, want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package omni
+
+// AnyChild reports whether pred is true for at least one immediate child,
+// stopping at the first match. Nil children are skipped.
+// pred runs over a snapshot of the children taken with ChildrenRef and is
+// called without holding any lock, so it may read or modify the atom.
+func (a *Atom) AnyChild(pred func(AtomInterface) bool) bool {
+	for _, child := range a.ChildrenRef() {
+		if child != nil && pred(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllChildren reports whether pred is true for every immediate child,
+// stopping at the first mismatch. It is true for an atom without children.
+// Nil children are skipped. pred is called as in AnyChild.
+func (a *Atom) AllChildren(pred func(AtomInterface) bool) bool {
+	for _, child := range a.ChildrenRef() {
+		if child != nil && !pred(child) {
+			return false
+		}
+	}
+	return true
+}
+
+// CountChildren returns the number of immediate children for which pred is
+// true. Nil children are skipped. pred is called as in AnyChild.
+func (a *Atom) CountChildren(pred func(AtomInterface) bool) int {
+	count := 0
+	for _, child := range a.ChildrenRef() {
+		if child != nil && pred(child) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,65 @@
+package omni
+
+import "testing"
+
+func isType(atomType string) func(AtomInterface) bool {
+	return func(atom AtomInterface) bool { return atom.GetType() == atomType }
+}
+
+func TestAtom_ChildPredicates(t *testing.T) {
+	page := NewAtom("page").(*Atom)
+	page.ChildAdd(NewAtom("image"))
+	page.ChildAdd(NewAtom("text"))
+	page.ChildAdd(NewAtom("image"))
+
+	if !page.AnyChild(isType("image")) || page.AnyChild(isType("video")) {
+		t.Fatal("unexpected AnyChild result")
+	}
+	if page.AllChildren(isType("image")) {
+		t.Fatal("expected AllChildren to be false with a text child")
+	}
+	if got := page.CountChildren(isType("image")); got != 2 {
+		t.Fatalf("CountChildren(image) = %d, want 2", got)
+	}
+
+	empty := NewAtom("page").(*Atom)
+	if empty.AnyChild(isType("image")) || !empty.AllChildren(isType("image")) || empty.CountChildren(isType("image")) != 0 {
+		t.Fatal("unexpected results for an atom without children")
+	}
+}
+
+func TestAtom_ChildPredicates_ShortCircuit(t *testing.T) {
+	page := NewAtom("page").(*Atom)
+	page.ChildAdd(NewAtom("image"))
+	page.ChildAdd(NewAtom("text"))
+	page.ChildAdd(NewAtom("image"))
+
+	calls := 0
+	page.AnyChild(func(atom AtomInterface) bool { calls++; return true })
+	if calls != 1 {
+		t.Fatalf("AnyChild called pred %d times, want 1", calls)
+	}
+
+	calls = 0
+	page.AllChildren(func(atom AtomInterface) bool { calls++; return atom.GetType() == "image" })
+	if calls != 2 {
+		t.Fatalf("AllChildren called pred %d times, want 2", calls)
+	}
+}
+
+func TestAtom_ChildPredicates_PredMayModifyParent(t *testing.T) {
+	page := NewAtom("page").(*Atom)
+	page.ChildAdd(NewAtom("image", WithID("a")))
+	page.ChildAdd(NewAtom("image", WithID("b")))
+
+	got := page.CountChildren(func(atom AtomInterface) bool {
+		page.ChildAdd(NewAtom("text"))
+		return page.ChildrenLength() > 0
+	})
+	if got != 2 {
+		t.Fatalf("CountChildren() = %d, want 2", got)
+	}
+	if page.ChildrenLength() != 4 {
+		t.Fatalf("ChildrenLength() = %d, want 4", page.ChildrenLength())
+	}
+}
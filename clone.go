@@ -0,0 +1,84 @@
+package omni
+
+import "slices"
+
+// CloneOptions configures CloneWith. The zero value makes a full deep clone.
+type CloneOptions struct {
+	// ShareChildren makes the clone reference the atom's children instead
+	// of deep copies of them. Shared children keep the atom as their Parent,
+	// so cloning never changes the original tree.
+	ShareChildren bool
+
+	// IncludeKeys, if not empty, keeps only the properties with these keys.
+	IncludeKeys []string
+
+	// ExcludeKeys drops the properties with these keys, such as "_cache".
+	// It is applied after IncludeKeys.
+	ExcludeKeys []string
+}
+
+// keepKey reports whether the property key is kept by the options.
+func (o CloneOptions) keepKey(key string) bool {
+	if len(o.IncludeKeys) > 0 && !slices.Contains(o.IncludeKeys, key) {
+		return false
+	}
+	return !slices.Contains(o.ExcludeKeys, key)
+}
+
+// filtersKeys reports whether the options drop any properties.
+func (o CloneOptions) filtersKeys() bool {
+	return len(o.IncludeKeys) > 0 || len(o.ExcludeKeys) > 0
+}
+
+// CloneWith returns a copy of the atom configured by opts.
+// With the zero CloneOptions it is a full deep clone, like Snapshot.
+// The property filters apply to the atom and, when children are deep
+// copied, to every copied descendant; shared children are left untouched.
+func (a *Atom) CloneWith(opts CloneOptions) AtomInterface {
+	if !opts.ShareChildren {
+		clone, _ := AsAtom(a.Snapshot())
+		if opts.filtersKeys() {
+			filterTreeProperties(clone, opts)
+		}
+		return clone
+	}
+
+	a.mu.RLock()
+	clone := &Atom{
+		id:         a.id,
+		atomType:   a.atomType,
+		properties: make(map[string]string, len(a.properties)),
+	}
+	for k, v := range a.properties {
+		if opts.keepKey(k) {
+			clone.properties[k] = v
+		}
+	}
+	a.mu.RUnlock()
+
+	// Assign the children without adopting them, which would re-parent
+	// the original's children to the clone
+	children := make([]AtomInterface, 0, a.ChildrenLength())
+	for _, child := range a.ChildrenRef() {
+		if child != nil {
+			children = append(children, child)
+		}
+	}
+	clone.children = children
+	return clone
+}
+
+// filterTreeProperties drops the properties not kept by opts from the atom
+// and its descendants. It must only be called on a fresh, unshared copy.
+func filterTreeProperties(atom *Atom, opts CloneOptions) {
+	for k := range atom.properties {
+		if !opts.keepKey(k) {
+			delete(atom.properties, k)
+		}
+	}
+	for _, child := range atom.children {
+		if childAtom, ok := AsAtom(child); ok {
+			filterTreeProperties(childAtom, opts)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package omni
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCloneWith_DefaultIsDeepClone(t *testing.T) {
+	root := newTestTree()
+
+	clone := root.CloneWith(CloneOptions{})
+	if !root.Equals(clone) {
+		t.Fatal("default clone should equal the original")
+	}
+
+	clone.ChildrenGet()[0].Set("name", "changed")
+	if root.ChildrenGet()[0].Get("name") != "intro" {
+		t.Fatal("deep clone should not share children with the original")
+	}
+}
+
+func TestCloneWith_ShareChildren(t *testing.T) {
+	root := newTestTree()
+
+	clone := root.CloneWith(CloneOptions{ShareChildren: true})
+	if clone.ChildrenGet()[0] != root.ChildrenGet()[0] {
+		t.Fatal("expected children to be shared")
+	}
+
+	clone.Set("title", "changed")
+	if root.Get("title") != "Home" {
+		t.Fatal("properties should still be copied when sharing children")
+	}
+
+	child := root.ChildrenGet()[0].(*Atom)
+	if child.Parent() != root || !child.IsDescendantOf(root) {
+		t.Fatal("sharing children must not re-parent them to the clone")
+	}
+	clone.ChildDeleteByID(child.GetID())
+	if child.Parent() != root {
+		t.Fatal("removing a shared child from the clone must not release it from the original")
+	}
+}
+
+func TestCloneWith_ExcludeKeys(t *testing.T) {
+	root := newTestTree()
+	root.Set("_cache", "x")
+	root.ChildrenGet()[0].Set("_cache", "y")
+
+	clone := root.CloneWith(CloneOptions{ExcludeKeys: []string{"_cache"}})
+	if clone.Has("_cache") || clone.ChildrenGet()[0].Has("_cache") {
+		t.Fatal("_cache should be dropped at every level")
+	}
+	if clone.Get("title") != "Home" || clone.ChildrenGet()[0].Get("name") != "intro" {
+		t.Fatal("other properties should be kept")
+	}
+	if !root.Has("_cache") || !root.ChildrenGet()[0].Has("_cache") {
+		t.Fatal("the original should be left unchanged")
+	}
+}
+
+func TestCloneWith_IncludeKeysWithSharedChildren(t *testing.T) {
+	root := newTestTree()
+	root.Set("_cache", "x")
+	root.ChildrenGet()[0].Set("_cache", "y")
+
+	clone := root.CloneWith(CloneOptions{
+		ShareChildren: true,
+		IncludeKeys:   []string{"title", "_cache"},
+		ExcludeKeys:   []string{"_cache"},
+	})
+	if got := clone.GetAll(); !reflect.DeepEqual(got, map[string]string{"title": "Home"}) {
+		t.Fatalf("clone properties = %v", got)
+	}
+	if !clone.ChildrenGet()[0].Has("_cache") {
+		t.Fatal("shared children should be left untouched")
+	}
+}
@@ -0,0 +1,100 @@
+package omni
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CommandType identifies the mutation a Command describes. The values are
+// strings so that stored event logs stay readable and stable.
+type CommandType string
+
+const (
+	// CommandSetProperty sets the property Key to Value on the target.
+	CommandSetProperty CommandType = "set_property"
+	// CommandRemoveProperty removes the property Key from the target.
+	CommandRemoveProperty CommandType = "remove_property"
+	// CommandSetType sets the type of the target to Value.
+	CommandSetType CommandType = "set_type"
+	// CommandAddChild appends a new child with ChildID and ChildType to the target.
+	CommandAddChild CommandType = "add_child"
+	// CommandRemoveChild removes the immediate child ChildID from the target.
+	CommandRemoveChild CommandType = "remove_child"
+)
+
+// Command is a single recorded mutation of a tree, such as an event in an
+// event log, replayed with ApplyCommands. TargetID identifies the atom the
+// command applies to; the other fields are used depending on Type.
+type Command struct {
+	Type     CommandType
+	TargetID string
+
+	Key   string
+	Value string
+
+	ChildID   string
+	ChildType string
+}
+
+// ApplyCommands replays cmds, in order, against the tree rooted at root,
+// for example to rebuild state from an event log.
+//
+// Business logic:
+// - Each target is looked up by ID in the whole tree, including added atoms
+// - Stops at the first failing command and returns an error naming it
+// - Commands applied before a failure are kept; there is no rollback
+// - A command whose target, or child for CommandRemoveChild, is missing fails
+// - CommandAddChild requires a ChildID, so replays are deterministic
+// - CommandSetProperty fails if an *Atom target rejects the write (see SetChecked)
+//
+// Parameters:
+//   - root: the root atom of the tree
+//   - cmds: the commands to apply
+//
+// Returns:
+//   - error: if root is nil or a command fails
+func ApplyCommands(root AtomInterface, cmds []Command) error {
+	if root == nil {
+		return errors.New("root atom cannot be nil")
+	}
+
+	for i, cmd := range cmds {
+		if err := applyCommand(root, cmd); err != nil {
+			return fmt.Errorf("command %d (%s): %w", i, cmd.Type, err)
+		}
+	}
+	return nil
+}
+
+// applyCommand applies a single command to the tree rooted at root.
+func applyCommand(root AtomInterface, cmd Command) error {
+	target := FindAtomByID(root, cmd.TargetID)
+	if target == nil {
+		return fmt.Errorf("target %q not found", cmd.TargetID)
+	}
+
+	switch cmd.Type {
+	case CommandSetProperty:
+		if atom, ok := AsAtom(target); ok {
+			return atom.SetChecked(cmd.Key, cmd.Value)
+		}
+		target.Set(cmd.Key, cmd.Value)
+	case CommandRemoveProperty:
+		target.Remove(cmd.Key)
+	case CommandSetType:
+		target.SetType(cmd.Value)
+	case CommandAddChild:
+		if cmd.ChildID == "" {
+			return errors.New("child ID cannot be empty")
+		}
+		target.ChildAdd(NewAtom(cmd.ChildType, WithID(cmd.ChildID)))
+	case CommandRemoveChild:
+		if target.ChildFindByID(cmd.ChildID) == nil {
+			return fmt.Errorf("child %q of %q not found", cmd.ChildID, cmd.TargetID)
+		}
+		target.ChildDeleteByID(cmd.ChildID)
+	default:
+		return fmt.Errorf("unknown command type %q", cmd.Type)
+	}
+	return nil
+}
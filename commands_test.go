@@ -0,0 +1,86 @@
+package omni
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestApplyCommands_RebuildsTree(t *testing.T) {
+	root := NewAtom("page", WithID("p1"))
+
+	cmds := []Command{
+		{Type: CommandSetProperty, TargetID: "p1", Key: "title", Value: "Home"},
+		{Type: CommandAddChild, TargetID: "p1", ChildID: "s1", ChildType: "section"},
+		{Type: CommandAddChild, TargetID: "s1", ChildID: "t1", ChildType: "text"},
+		{Type: CommandSetProperty, TargetID: "t1", Key: "content", Value: "Hello"},
+		{Type: CommandAddChild, TargetID: "s1", ChildID: "t2", ChildType: "text"},
+		{Type: CommandRemoveChild, TargetID: "s1", ChildID: "t2"},
+		{Type: CommandSetType, TargetID: "s1", Value: "hero"},
+		{Type: CommandSetProperty, TargetID: "p1", Key: "draft", Value: "yes"},
+		{Type: CommandRemoveProperty, TargetID: "p1", Key: "draft"},
+	}
+	if err := ApplyCommands(root, cmds); err != nil {
+		t.Fatalf("ApplyCommands() error = %v", err)
+	}
+
+	want := NewAtom("page", WithID("p1"), WithProperty("title", "Home"))
+	hero := NewAtom("hero", WithID("s1"))
+	hero.ChildAdd(NewAtom("text", WithID("t1"), WithProperty("content", "Hello")))
+	want.ChildAdd(hero)
+
+	if diff := ExplainDiff(want, root); diff != "" {
+		t.Fatalf("unexpected tree: %s", diff)
+	}
+}
+
+func TestApplyCommands_Errors(t *testing.T) {
+	tests := []struct {
+		cmd  Command
+		want string
+	}{
+		{Command{Type: CommandSetProperty, TargetID: "missing", Key: "k"}, `command 1 (set_property): target "missing" not found`},
+		{Command{Type: CommandRemoveChild, TargetID: "p1", ChildID: "missing"}, `child "missing" of "p1" not found`},
+		{Command{Type: CommandAddChild, TargetID: "p1", ChildType: "text"}, "child ID cannot be empty"},
+		{Command{Type: "rename", TargetID: "p1"}, `unknown command type "rename"`},
+	}
+
+	for _, tt := range tests {
+		root := NewAtom("page", WithID("p1"))
+		cmds := []Command{
+			{Type: CommandSetProperty, TargetID: "p1", Key: "title", Value: "Home"},
+			tt.cmd,
+			{Type: CommandSetProperty, TargetID: "p1", Key: "after", Value: "x"},
+		}
+
+		err := ApplyCommands(root, cmds)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Fatalf("ApplyCommands() error = %v, want %q", err, tt.want)
+		}
+		if root.Get("title") != "Home" || root.Has("after") {
+			t.Fatal("commands before the failure should be kept and later ones skipped")
+		}
+	}
+
+	if err := ApplyCommands(nil, nil); err == nil {
+		t.Fatal("expected an error for a nil root")
+	}
+}
+
+func TestApplyCommands_RejectedSetProperty(t *testing.T) {
+	reject := func(key string) (string, error) {
+		if key == "bad" {
+			return "", errors.New("reserved key")
+		}
+		return key, nil
+	}
+	root := NewAtom("page", WithID("p1"), WithKeyValidator(reject))
+
+	err := ApplyCommands(root, []Command{{Type: CommandSetProperty, TargetID: "p1", Key: "bad", Value: "x"}})
+	if err == nil || !strings.Contains(err.Error(), "reserved key") {
+		t.Fatalf("ApplyCommands() error = %v, want the validator error", err)
+	}
+	if root.Has("bad") {
+		t.Fatal("expected the rejected property not to be set")
+	}
+}
@@ -1,11 +1,8 @@
 package omni
 
-import (
-	"github.com/dracory/uid"
-)
-
 // NewAtom creates a new Atom with the given type and applies the provided options.
-// If no ID is provided via options, a human-readable UID will be generated.
+// If no ID is provided via options, one is generated, by default a
+// human-readable UID (see SetIDGenerator).
 // Returns an AtomInterface to maintain consistency with other constructors.
 func NewAtom(atomType string, opts ...AtomOption) AtomInterface {
 	atom := &Atom{
@@ -22,7 +19,7 @@ func NewAtom(atomType string, opts ...AtomOption) AtomInterface {
 
 	// If no ID was set by options, generate one
 	if atom.id == "" {
-		atom.id = uid.HumanUid()
+		atom.id = generateID()
 	}
 
 	return atom
@@ -106,22 +103,95 @@ func WithID(id string) AtomOption {
 
 // WithProperties adds properties to the Atom.
 // Note: This will not set 'id' or 'type' as they are now direct fields.
+// Properties rejected by options set earlier (see WithKeyValidator and
+// WithMaxValueLength) are skipped.
 func WithProperties(properties map[string]string) AtomOption {
 	return func(a *Atom) {
 		a.mu.Lock()
 		defer a.mu.Unlock()
 		for k, v := range properties {
-			if k != "id" && k != "type" {
-				a.properties[k] = v
+			if k == "id" || k == "type" {
+				continue
+			}
+			key, value, err := a.preparePropertyLocked(k, v)
+			if err != nil {
+				continue
 			}
+			a.properties[key] = value
 		}
 	}
 }
 
+// WithProperty sets a single property on the Atom.
+// Like WithProperties, it will not set 'id' or 'type'.
+// Multiple WithProperty options can be combined in a single NewAtom call.
+func WithProperty(key, value string) AtomOption {
+	return WithProperties(map[string]string{key: value})
+}
+
 // WithChildren adds child atoms to the Atom.
+// Nil children are filtered out, like in ChildrenAdd.
 func WithChildren(children ...AtomInterface) AtomOption {
 	return func(a *Atom) {
-		a.children = append(a.children, children...)
+		a.ChildrenAdd(children)
+	}
+}
+
+// WithParent appends the Atom to the given parent's children.
+// If parent is nil, it's a no-op.
+//
+// The atom is attached when the option is applied, but since the parent holds
+// a reference to the same atom, any options applied afterwards (including
+// WithChildren and the generated ID) are visible through the parent.
+// WithParent and WithChildren are independent: WithParent places the atom
+// under its parent, WithChildren places other atoms under the atom,
+// and their relative order in the option list does not matter.
+func WithParent(parent AtomInterface) AtomOption {
+	return func(a *Atom) {
+		if parent == nil {
+			return
+		}
+		parent.ChildAdd(a)
+	}
+}
+
+// WithUniqueChildIDs makes all add operations (ChildAdd, ChildrenAdd, ChildAddAll
+// and ChildrenSet) skip children whose ID is already used by an immediate child.
+// Place it before WithChildren so the initial children are also checked.
+func WithUniqueChildIDs() AtomOption {
+	return func(a *Atom) {
+		a.childrenMu.Lock()
+		defer a.childrenMu.Unlock()
+		a.uniqueChildIDs = true
+	}
+}
+
+// WithKeyValidator sets a function that validates and normalizes property
+// keys on every property write (Set, SetChecked, SetIfAbsent, SetAll,
+// PropertiesSet, WithProperties, WithData, FromGob, UnmarshalJSON, ...).
+// The returned string is used as the key; a returned error rejects the write
+// (reported by SetChecked, SetAllChecked and the decoders).
+// Place it before WithProperties so the initial properties are also checked.
+// The validator is called while the atom is locked and must not call its methods.
+func WithKeyValidator(validator func(key string) (string, error)) AtomOption {
+	return func(a *Atom) {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.keyValidator = validator
+	}
+}
+
+// WithInterner makes the Atom intern property keys and values with the given
+// Interner on every property write (Set, SetChecked, SetIfAbsent, SetAll,
+// PropertiesSet, WithProperties, WithData, FromGob, UnmarshalJSON, ...),
+// so atoms sharing the Interner store repeated strings once. It only affects
+// memory usage, not behavior. Place it before WithProperties so the initial
+// properties are also interned. A nil interner disables interning.
+func WithInterner(interner *Interner) AtomOption {
+	return func(a *Atom) {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.interner = interner
 	}
 }
 
@@ -133,7 +203,8 @@ func WithType(atomType string) AtomOption {
 }
 
 // FromGob decodes an Atom from gob-encoded data.
-// This is a helper function that creates a new Atom and calls FromGob on it.
+// This is a helper function that creates a new Atom and calls FromGob on it,
+// so atoms nested more than DefaultMaxDepth levels deep are rejected.
 func FromGob(data []byte) (*Atom, error) {
 	atom := &Atom{}
 	if err := atom.FromGob(data); err != nil {
@@ -141,3 +212,15 @@ func FromGob(data []byte) (*Atom, error) {
 	}
 	return atom, nil
 }
+
+// FromGobShallow decodes an Atom from data produced by ToGobShallow.
+// Only the id, type and properties are decoded; any encoded children are ignored,
+// so the returned atom never has children.
+func FromGobShallow(data []byte) (*Atom, error) {
+	atom, err := FromGob(data)
+	if err != nil {
+		return nil, err
+	}
+	atom.ChildrenSet([]AtomInterface{})
+	return atom, nil
+}
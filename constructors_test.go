@@ -358,3 +358,98 @@ func TestFromGob_WithInvalidData(t *testing.T) {
 		t.Fatalf("expected error and nil atom for empty gob, got atom=%v err=%v", atom, err)
 	}
 }
+
+func TestToGobShallow_RoundTripWithoutChildren(t *testing.T) {
+	root := omni.NewAtom("root", omni.WithID("root"), omni.WithProperties(map[string]string{
+		"title": "hello",
+	}))
+	root.ChildAdd(omni.NewAtom("child", omni.WithID("c1")))
+	root.ChildAdd(omni.NewAtom("child", omni.WithID("c2")))
+
+	data, err := root.(*omni.Atom).ToGobShallow()
+	if err != nil {
+		t.Fatalf("ToGobShallow() error = %v", err)
+	}
+
+	decoded, err := omni.FromGobShallow(data)
+	if err != nil {
+		t.Fatalf("FromGobShallow() error = %v", err)
+	}
+	if decoded.GetID() != "root" || decoded.GetType() != "root" || decoded.Get("title") != "hello" {
+		t.Fatalf("unexpected decoded atom: id=%q type=%q title=%q", decoded.GetID(), decoded.GetType(), decoded.Get("title"))
+	}
+	if decoded.ChildrenLength() != 0 {
+		t.Fatalf("expected no children after shallow decode, got %d", decoded.ChildrenLength())
+	}
+
+	// The shallow encoding is also a valid regular gob atom
+	viaGob, err := omni.GobToAtom(data)
+	if err != nil {
+		t.Fatalf("GobToAtom() error = %v", err)
+	}
+	if viaGob.ChildrenLength() != 0 {
+		t.Fatalf("expected no children via GobToAtom, got %d", viaGob.ChildrenLength())
+	}
+}
+
+func TestWithProperty_Chained(t *testing.T) {
+	atom := omni.NewAtom("person",
+		omni.WithID("p1"),
+		omni.WithProperty("name", "Alice"),
+		omni.WithProperty("email", "alice@example.com"),
+		omni.WithProperty("role", "admin"),
+	)
+
+	want := map[string]string{
+		"name":  "Alice",
+		"email": "alice@example.com",
+		"role":  "admin",
+	}
+	if got := atom.GetAll(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("GetAll() = %v, want %v", got, want)
+	}
+}
+
+func TestWithProperty_IgnoresIDAndType(t *testing.T) {
+	atom := omni.NewAtom("person",
+		omni.WithID("p1"),
+		omni.WithProperty("id", "other"),
+		omni.WithProperty("type", "other"),
+	)
+
+	if atom.GetID() != "p1" || atom.GetType() != "person" {
+		t.Fatalf("unexpected id/type: %q/%q", atom.GetID(), atom.GetType())
+	}
+	if atom.Has("id") || atom.Has("type") {
+		t.Fatal("WithProperty should not store id or type as properties")
+	}
+}
+
+func TestWithParent_AttachesToParent(t *testing.T) {
+	parent := omni.NewAtom("parent", omni.WithID("p"))
+	grandchild := omni.NewAtom("grandchild", omni.WithID("g"))
+
+	child := omni.NewAtom("child",
+		omni.WithParent(parent),
+		omni.WithChildren(grandchild),
+		omni.WithProperty("name", "child"),
+	)
+
+	children := parent.ChildrenGet()
+	if len(children) != 1 || children[0] != child {
+		t.Fatalf("expected parent to contain the new child, got %v", children)
+	}
+	if children[0].GetID() == "" {
+		t.Fatal("expected generated ID to be visible through the parent")
+	}
+	if children[0].Get("name") != "child" || children[0].ChildrenLength() != 1 {
+		t.Fatal("expected options applied after WithParent to be visible through the parent")
+	}
+}
+
+func TestWithParent_NilParentIsNoop(t *testing.T) {
+	atom := omni.NewAtom("child", omni.WithID("c"), omni.WithParent(nil))
+	if atom.GetID() != "c" {
+		t.Fatalf("unexpected id: %q", atom.GetID())
+	}
+}
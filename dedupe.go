@@ -0,0 +1,46 @@
+package omni
+
+// Dedupe removes atoms that are structurally equal (see Equals) to an
+// earlier atom in the slice.
+//
+// Business logic:
+//   - Handles nil input by returning nil
+//   - Preserves the order in which atoms are first seen
+//   - Groups atoms by Hash, so only atoms with the same hash are compared
+//     with Equals, instead of comparing every pair
+//   - Nil atoms are equal to each other, so only the first nil is kept
+//   - The input slice is not modified
+//
+// Parameters:
+//   - atoms: the atoms to deduplicate
+//
+// Returns:
+//   - []AtomInterface: the atoms without structural duplicates
+func Dedupe(atoms []AtomInterface) []AtomInterface {
+	if atoms == nil {
+		return nil
+	}
+
+	result := make([]AtomInterface, 0, len(atoms))
+	seen := make(map[string][]AtomInterface, len(atoms))
+
+	for _, atom := range atoms {
+		key := atomHash(atom)
+
+		duplicate := false
+		for _, candidate := range seen[key] {
+			if atomsEqual(candidate, atom) {
+				duplicate = true
+				break
+			}
+		}
+		if duplicate {
+			continue
+		}
+
+		seen[key] = append(seen[key], atom)
+		result = append(result, atom)
+	}
+
+	return result
+}
@@ -0,0 +1,27 @@
+package omni
+
+import "testing"
+
+func TestDedupe_RemovesStructuralDuplicates(t *testing.T) {
+	first := newTestTree()
+	other := newTestTree().Set("title", "About")
+	duplicate := newTestTree()
+
+	got := Dedupe([]AtomInterface{first, other, duplicate, nil, other, nil})
+
+	if len(got) != 3 {
+		t.Fatalf("expected 3 atoms, got %d", len(got))
+	}
+	if got[0] != first || got[1] != other || got[2] != nil {
+		t.Fatalf("expected first-seen order to be preserved, got %v", got)
+	}
+}
+
+func TestDedupe_NilAndEmpty(t *testing.T) {
+	if got := Dedupe(nil); got != nil {
+		t.Fatalf("Dedupe(nil) = %v, want nil", got)
+	}
+	if got := Dedupe([]AtomInterface{}); got == nil || len(got) != 0 {
+		t.Fatalf("Dedupe(empty) = %v, want empty slice", got)
+	}
+}
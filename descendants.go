@@ -0,0 +1,22 @@
+package omni
+
+// Finding atoms by type, by scope:
+//
+//   - ChildFindByType: first immediate child
+//   - ChildrenFindByType: all immediate children
+//   - FindFirstDescendantByType: first descendant at any depth
+//   - DescendantsByType: all descendants at any depth
+//   - FindFirstAtomByType, FindAtomsByType: like the descendant finders, but
+//     free functions that also consider the root they are given
+
+// FindFirstDescendantByType returns the first atom with the given type among
+// the atom's descendants at any depth, in pre-order, or nil if there is none.
+// The atom itself is not considered.
+func (a *Atom) FindFirstDescendantByType(atomType string) AtomInterface {
+	for _, child := range a.ChildrenGet() {
+		if found := FindFirstAtomByType(child, atomType); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package omni
+
+import "testing"
+
+func TestAtom_FindFirstDescendantByType(t *testing.T) {
+	root := NewAtom("section", WithID("root")).(*Atom)
+	first := NewAtom("section", WithID("s1"))
+	first.ChildAdd(NewAtom("text", WithID("t1")))
+	root.ChildAdd(NewAtom("text", WithID("t0")))
+	root.ChildAdd(first)
+	root.ChildAdd(NewAtom("section", WithID("s2")))
+
+	if got := root.FindFirstDescendantByType("section"); got == nil || got.GetID() != "s1" {
+		t.Fatalf("FindFirstDescendantByType() = %v, want s1 (the root is excluded)", got)
+	}
+	if got := first.(*Atom).FindFirstDescendantByType("text"); got == nil || got.GetID() != "t1" {
+		t.Fatalf("FindFirstDescendantByType() = %v, want t1", got)
+	}
+	if got := root.FindFirstDescendantByType("image"); got != nil {
+		t.Fatalf("FindFirstDescendantByType() = %v, want nil", got)
+	}
+
+}
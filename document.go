@@ -0,0 +1,70 @@
+package omni
+
+import "sync"
+
+// Document holds several top-level atoms (roots), for formats that store
+// more than one tree without a synthetic wrapper root.
+// Its JSON form is an array of atoms, the same shape as AtomsToJSON.
+// A Document is safe for concurrent use.
+type Document struct {
+	roots []AtomInterface
+	mu    sync.RWMutex
+}
+
+// NewDocument creates a Document with the given roots. Nil roots are skipped.
+func NewDocument(roots ...AtomInterface) *Document {
+	d := &Document{roots: make([]AtomInterface, 0, len(roots))}
+	for _, root := range roots {
+		d.AddRoot(root)
+	}
+	return d
+}
+
+// AddRoot appends a root atom to the document. A nil root is ignored.
+func (d *Document) AddRoot(root AtomInterface) {
+	if root == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.roots = append(d.roots, root)
+}
+
+// Roots returns a copy of the document's root atoms.
+func (d *Document) Roots() []AtomInterface {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	roots := make([]AtomInterface, len(d.roots))
+	copy(roots, d.roots)
+	return roots
+}
+
+// FindByID searches the roots in order, each in pre-order, and returns the
+// first atom with the given ID, or nil if there is none.
+func (d *Document) FindByID(id string) AtomInterface {
+	for _, root := range d.Roots() {
+		if found := FindAtomByID(root, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// ToJSON serializes the roots as a JSON array (see AtomsToJSON).
+func (d *Document) ToJSON() (string, error) {
+	return AtomsToJSON(d.Roots())
+}
+
+// FromJSON replaces the roots with the atoms parsed from a JSON array
+// (see JSONToAtoms). On error the document is left unchanged.
+func (d *Document) FromJSON(jsonStr string) error {
+	roots, err := JSONToAtoms(jsonStr)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.roots = roots
+	return nil
+}
@@ -0,0 +1,64 @@
+package omni
+
+import "testing"
+
+func TestDocument_AddRootAndFindByID(t *testing.T) {
+	first := NewAtom("page", WithID("p1"))
+	second := NewAtom("page", WithID("p2"))
+	second.ChildAdd(NewAtom("text", WithID("t1")))
+
+	doc := NewDocument(first, nil)
+	doc.AddRoot(second)
+	doc.AddRoot(nil)
+
+	if got := len(doc.Roots()); got != 2 {
+		t.Fatalf("expected 2 roots, got %d", got)
+	}
+	if found := doc.FindByID("t1"); found == nil || found.GetType() != "text" {
+		t.Fatalf("FindByID(t1) = %v", found)
+	}
+	if found := doc.FindByID("p1"); found != first {
+		t.Fatal("FindByID(p1) should return the first root")
+	}
+	if doc.FindByID("missing") != nil {
+		t.Fatal("FindByID(missing) should return nil")
+	}
+}
+
+func TestDocument_JSONRoundTrip(t *testing.T) {
+	root := NewAtom("page", WithID("p1"), WithProperty("title", "Home"))
+	root.ChildAdd(NewAtom("text", WithID("t1")))
+	doc := NewDocument(root, NewAtom("page", WithID("p2")))
+
+	jsonStr, err := doc.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON() error = %v", err)
+	}
+	want, _ := AtomsToJSON(doc.Roots())
+	if jsonStr != want {
+		t.Fatalf("ToJSON() = %s, want %s", jsonStr, want)
+	}
+
+	loaded := NewDocument()
+	if err := loaded.FromJSON(jsonStr); err != nil {
+		t.Fatalf("FromJSON() error = %v", err)
+	}
+	roots := loaded.Roots()
+	if len(roots) != 2 || roots[0].GetID() != "p1" || roots[1].GetID() != "p2" {
+		t.Fatalf("unexpected roots after FromJSON: %v", roots)
+	}
+	if loaded.FindByID("t1") == nil {
+		t.Fatal("expected t1 to be found after FromJSON")
+	}
+}
+
+func TestDocument_FromJSON_InvalidLeavesDocumentUnchanged(t *testing.T) {
+	doc := NewDocument(NewAtom("page", WithID("p1")))
+
+	if err := doc.FromJSON("not json"); err == nil {
+		t.Fatal("expected an error for invalid JSON")
+	}
+	if roots := doc.Roots(); len(roots) != 1 || roots[0].GetID() != "p1" {
+		t.Fatalf("document changed after failed FromJSON: %v", roots)
+	}
+}
@@ -0,0 +1,104 @@
+package omni
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"sort"
+)
+
+// Equals reports whether the atom and other are structurally equal: same id,
+// type and properties, and pairwise equal children in the same order,
+// recursively. Other implementations of AtomInterface are compared through
+// their interface methods. A nil other is never equal to a non-nil atom.
+func (a *Atom) Equals(other AtomInterface) bool {
+	return atomsEqual(a, other)
+}
+
+// EqualsIgnoreID reports whether the atom and other have the same shape:
+// same type and properties, and pairwise EqualsIgnoreID children in the same
+// order, recursively. Unlike Equals, ids are ignored at every level, so two
+// instances of the same template compare equal. Nil handling matches Equals:
+// a nil other is never equal, and nil children only match nil children.
+func (a *Atom) EqualsIgnoreID(other AtomInterface) bool {
+	return atomsEqualWith(a, other, true)
+}
+
+// Hash returns a hex-encoded SHA-256 hash of the atom's id, type, properties
+// and children, recursively. Atoms that are Equals have the same hash, so it
+// can be used as a map key to find structural duplicates.
+func (a *Atom) Hash() string {
+	return atomHash(a)
+}
+
+// atomsEqual compares two atoms through the AtomInterface methods.
+// Two nil atoms are equal.
+func atomsEqual(a, b AtomInterface) bool {
+	return atomsEqualWith(a, b, false)
+}
+
+// atomsEqualWith compares two atoms like atomsEqual, skipping the ids
+// at every level if ignoreID is set. It shares its walk with ExplainDiff.
+func atomsEqualWith(a, b AtomInterface, ignoreID bool) bool {
+	return firstDiff(a, b, ignoreID) == nil
+}
+
+// atomHash returns the hex-encoded hash of an atom, as described by Hash.
+func atomHash(atom AtomInterface) string {
+	h := sha256.New()
+	writeAtomHash(h, atom)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeAtomHash writes an unambiguous encoding of the atom to h.
+// Every string is length-prefixed and properties are written in key order.
+func writeAtomHash(h hash.Hash, atom AtomInterface) {
+	if isNilAtom(atom) {
+		writeHashUint(h, 0)
+		return
+	}
+	writeHashUint(h, 1)
+
+	writeHashString(h, atom.GetID())
+	writeHashString(h, atom.GetType())
+
+	props := atom.GetAll()
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	writeHashUint(h, uint64(len(keys)))
+	for _, k := range keys {
+		writeHashString(h, k)
+		writeHashString(h, props[k])
+	}
+
+	children := atom.ChildrenGet()
+	writeHashUint(h, uint64(len(children)))
+	for _, child := range children {
+		writeAtomHash(h, child)
+	}
+}
+
+func writeHashUint(h hash.Hash, n uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], n)
+	h.Write(buf[:])
+}
+
+func writeHashString(h hash.Hash, s string) {
+	writeHashUint(h, uint64(len(s)))
+	h.Write([]byte(s))
+}
+
+// isNilAtom reports whether atom is nil, including a nil *Atom
+// stored in a non-nil interface.
+func isNilAtom(atom AtomInterface) bool {
+	if atom == nil {
+		return true
+	}
+	a, ok := atom.(*Atom)
+	return ok && a == nil
+}
@@ -0,0 +1,91 @@
+package omni
+
+import "testing"
+
+func TestAtom_Equals(t *testing.T) {
+	a := newTestTree()
+	b := newTestTree()
+
+	if !a.Equals(b) || !b.Equals(a) {
+		t.Fatal("expected structurally identical trees to be equal")
+	}
+	if a.Hash() != b.Hash() {
+		t.Fatal("expected equal trees to have the same hash")
+	}
+
+	if a.Equals(nil) {
+		t.Error("expected atom not to equal nil")
+	}
+	if !a.Equals(customAtom{AtomInterface: b}) {
+		t.Error("expected other implementations to be compared through the interface")
+	}
+}
+
+func TestAtom_Equals_Differences(t *testing.T) {
+	base := newTestTree()
+
+	tests := map[string]func(*Atom){
+		"id":         func(a *Atom) { a.SetID("other") },
+		"type":       func(a *Atom) { a.SetType("other") },
+		"property":   func(a *Atom) { a.Set("title", "About") },
+		"extra prop": func(a *Atom) { a.Set("extra", "") },
+		"grandchild": func(a *Atom) { a.ChildFindByID("s1").ChildFindByID("t1").Set("x", "y") },
+		"child":      func(a *Atom) { a.ChildAdd(NewAtom("text", WithID("t3"))) },
+	}
+
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			other := newTestTree()
+			mutate(other)
+			if base.Equals(other) {
+				t.Fatal("expected trees to differ")
+			}
+			if base.Hash() == other.Hash() {
+				t.Fatal("expected different hashes")
+			}
+		})
+	}
+}
+
+func TestAtom_Hash_Unambiguous(t *testing.T) {
+	a := NewAtom("t", WithID("ab"), WithProperty("c", "")).(*Atom)
+	b := NewAtom("t", WithID("a"), WithProperty("bc", "")).(*Atom)
+	if a.Hash() == b.Hash() {
+		t.Fatal("expected field boundaries to be part of the hash")
+	}
+}
+
+func TestAtom_EqualsIgnoreID(t *testing.T) {
+	a := newTestTree()
+	b := newTestTree()
+	ReassignIDs(b, nil)
+
+	if a.Equals(b) {
+		t.Fatal("expected trees with different ids not to be Equals")
+	}
+	if !a.EqualsIgnoreID(b) || !b.EqualsIgnoreID(a) {
+		t.Fatal("expected trees differing only by ids to be EqualsIgnoreID")
+	}
+
+	if a.EqualsIgnoreID(newTestTree().Set("title", "Other")) {
+		t.Error("expected different properties not to be EqualsIgnoreID")
+	}
+	b.ChildrenGet()[0].ChildrenGet()[0].SetType("image")
+	if a.EqualsIgnoreID(b) {
+		t.Error("expected a different descendant type not to be EqualsIgnoreID")
+	}
+	if a.EqualsIgnoreID(nil) {
+		t.Error("expected atom not to equal nil")
+	}
+}
+
+func BenchmarkAtom_Equals(b *testing.B) {
+	tree := buildBenchmarkTree(4, 5).(*Atom)
+	clone := tree.CloneWith(CloneOptions{})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if !tree.Equals(clone) {
+			b.Fatal("expected the clone to be equal")
+		}
+	}
+}
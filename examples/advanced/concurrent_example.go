@@ -56,7 +56,7 @@ func main() {
 			if len(children) > 0 {
 				mu.Lock()
 				for _, child := range children {
-					doc = doc.ChildAdd(child).(*omni.Atom)
+					doc = doc.ChildAdd(child)
 				}
 				mu.Unlock()
 			}
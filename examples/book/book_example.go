@@ -29,24 +29,6 @@ func createPage(id string, pageNum int, content1, content2 string) omni.AtomInte
 	)
 }
 
-// printBook recursively prints the book structure
-func printBook(atom omni.AtomInterface, indent int) {
-	prefix := ""
-	for i := 0; i < indent; i++ {
-		prefix += "  "
-	}
-
-	fmt.Printf("%s- %s (type: %s)\n", prefix, atom.GetID(), atom.GetType())
-	props := atom.GetAll()
-	for key, value := range props {
-		fmt.Printf("%s  %s: %v\n", prefix, key, value)
-	}
-
-	for _, child := range atom.ChildrenGet() {
-		printBook(child, indent+1)
-	}
-}
-
 func main() {
 	// Create pages with properties
 	var pages []omni.AtomInterface
@@ -75,7 +57,7 @@ func main() {
 
 	// Print the book structure
 	fmt.Println("Book structure:")
-	printBook(book, 0)
+	fmt.Print(omni.RenderTree(book))
 
 	// Convert to JSON and print
 	jsonData, _ := book.ToJSON()
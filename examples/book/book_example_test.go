@@ -41,7 +41,7 @@ func TestCreatePage(t *testing.T) {
 	}
 }
 
-// TestPrintBook tests the printBook function
+// TestPrintBook tests the book structure that main prints
 func TestPrintBook(t *testing.T) {
 	// Create a test book structure
 	book := omni.NewAtom("book",
@@ -33,7 +33,7 @@ func createPage(site omni.AtomInterface, pageID, uri, title, headerText, paragra
 			"text": headerText,
 		}),
 	)
-	page = page.ChildAdd(header).(*omni.Atom)
+	page = page.ChildAdd(header)
 
 	// Add paragraph
 	paragraph := omni.NewAtom("paragraph",
@@ -42,37 +42,37 @@ func createPage(site omni.AtomInterface, pageID, uri, title, headerText, paragra
 			"content": paragraphText,
 		}),
 	)
-	page = page.ChildAdd(paragraph).(*omni.Atom)
+	page = page.ChildAdd(paragraph)
 
 	// Add page to site and return the updated site
 	site = site.ChildAdd(page)
 	return site
 }
 
-// printWebsite recursively prints the website structure
-func printWebsite(atom omni.AtomInterface, indent string) {
-	// Print current atom
-	fmt.Printf("%s- %s (%s)", indent, atom.GetID(), atom.GetType())
-
-	// Print properties if any
-	props := atom.GetAll()
-	if len(props) > 0 {
-		fmt.Print(" - Properties: ")
-		first := true
-		for key, value := range props {
-			if !first {
-				fmt.Print(", ")
-			}
-			fmt.Printf("%s: %q", key, value)
-			first = false
+// pageRenderers maps the website atom types to their HTML
+var pageRenderers = map[string]func(omni.AtomInterface, string) string{
+	"page": func(page omni.AtomInterface, children string) string {
+		html := "<!DOCTYPE html>\n<html>\n<head>\n"
+		if title := page.Get("title"); title != "" {
+			html += fmt.Sprintf("  <title>%s</title>\n", title)
 		}
-	}
-	fmt.Println()
-
-	// Recursively print children
-	for _, child := range atom.ChildrenGet() {
-		printWebsite(child, indent+"  ")
-	}
+		html += "</head>\n<body>\n"
+		html += children
+		html += "</body>\n</html>"
+		return html
+	},
+	"header": func(header omni.AtomInterface, _ string) string {
+		if text := header.Get("text"); text != "" {
+			return fmt.Sprintf("  <h1>%s</h1>\n", text)
+		}
+		return ""
+	},
+	"paragraph": func(paragraph omni.AtomInterface, _ string) string {
+		if content := paragraph.Get("content"); content != "" {
+			return fmt.Sprintf("  <p>%s</p>\n", content)
+		}
+		return ""
+	},
 }
 
 // renderPage renders a single page as HTML
@@ -81,27 +81,10 @@ func renderPage(page omni.AtomInterface) string {
 		return ""
 	}
 
-	html := "<!DOCTYPE html>\n<html>\n<head>\n"
-	if title := page.Get("title"); title != "" {
-		html += fmt.Sprintf("  <title>%s</title>\n", title)
-	}
-	html += "</head>\n<body>\n"
-
-	// Add header and content
-	for _, child := range page.ChildrenGet() {
-		switch child.GetType() {
-		case "header":
-			if text := child.Get("text"); text != "" {
-				html += fmt.Sprintf("  <h1>%s</h1>\n", text)
-			}
-		case "paragraph":
-			if content := child.Get("content"); content != "" {
-				html += fmt.Sprintf("  <p>%s</p>\n", content)
-			}
-		}
+	html, err := omni.RenderHTML(page, pageRenderers)
+	if err != nil {
+		return ""
 	}
-
-	html += "</body>\n</html>"
 	return html
 }
 
@@ -146,7 +129,7 @@ func main() {
 
 	// Print the website structure
 	fmt.Println("Website structure:")
-	printWebsite(site, "  ")
+	fmt.Print(omni.RenderTree(site))
 
 	// Set up HTTP server
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
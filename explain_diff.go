@@ -0,0 +1,149 @@
+package omni
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// ExplainDiff describes the first structural difference between a and b, in
+// the same order Equals compares them, or returns "" if they are equal.
+//
+// Business logic:
+// - Compares ids, types, properties (in key order), then children pairwise
+// - Extra children are reported after the common ones match
+// - Locations use the GetByPath syntax, such as "children/0/properties/title"
+// - The root is reported as "root"; nil atoms are reported as nil
+//
+// Example output:
+//
+//	at children/1/properties/title: "Home" != "Away"
+//
+// Parameters:
+//   - a: the first atom
+//   - b: the second atom
+//
+// Returns:
+//   - string: the first difference, or "" if the atoms are equal
+func ExplainDiff(a, b AtomInterface) string {
+	if diff := firstDiff(a, b, false); diff != nil {
+		return diff.String()
+	}
+	return ""
+}
+
+// atomDiff describes the first difference found by firstDiff.
+type atomDiff struct {
+	// segments is the path of the differing atom, from the atom up to the
+	// root; it is only filled in once a difference is found.
+	segments []string
+	// field is the differing field, such as "id" or "properties/title",
+	// or "" when the atoms themselves differ.
+	field  string
+	detail string
+}
+
+// String formats the difference as described by ExplainDiff.
+func (d *atomDiff) String() string {
+	path := ""
+	for i := len(d.segments) - 1; i >= 0; i-- {
+		path = joinPath(path, d.segments[i])
+	}
+	if d.field != "" {
+		path = joinPath(path, d.field)
+	}
+	if path == "" {
+		path = "root"
+	}
+	return fmt.Sprintf("at %s: %s", path, d.detail)
+}
+
+// firstDiff returns the first difference between a and b, in the order
+// described by ExplainDiff, or nil if they are equal. The ids are skipped at
+// every level if ignoreID is set. It is the single walk behind Equals,
+// EqualsIgnoreID and ExplainDiff, and builds no paths for equal atoms.
+func firstDiff(a, b AtomInterface, ignoreID bool) *atomDiff {
+	switch {
+	case isNilAtom(a) && isNilAtom(b):
+		return nil
+	case isNilAtom(a):
+		return &atomDiff{detail: "a is nil, b is not"}
+	case isNilAtom(b):
+		return &atomDiff{detail: "b is nil, a is not"}
+	}
+
+	if aID, bID := a.GetID(), b.GetID(); !ignoreID && aID != bID {
+		return &atomDiff{field: "id", detail: fmt.Sprintf("%q != %q", aID, bID)}
+	}
+	if aType, bType := a.GetType(), b.GetType(); aType != bType {
+		return &atomDiff{field: "type", detail: fmt.Sprintf("%q != %q", aType, bType)}
+	}
+
+	if aProps, bProps := a.GetAll(), b.GetAll(); !propertiesEqual(aProps, bProps) {
+		return propertiesDiff(aProps, bProps)
+	}
+
+	aChildren, bChildren := a.ChildrenGet(), b.ChildrenGet()
+	for i := 0; i < len(aChildren) && i < len(bChildren); i++ {
+		if diff := firstDiff(aChildren[i], bChildren[i], ignoreID); diff != nil {
+			diff.segments = append(diff.segments, "children/"+strconv.Itoa(i))
+			return diff
+		}
+	}
+	if len(aChildren) != len(bChildren) {
+		return &atomDiff{detail: fmt.Sprintf("%d children != %d children", len(aChildren), len(bChildren))}
+	}
+
+	return nil
+}
+
+// propertiesEqual reports whether two property maps hold the same entries.
+func propertiesEqual(aProps, bProps map[string]string) bool {
+	if len(aProps) != len(bProps) {
+		return false
+	}
+	for k, v := range aProps {
+		if bv, ok := bProps[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// propertiesDiff describes the first differing property, by key order,
+// of two property maps that are not equal.
+func propertiesDiff(aProps, bProps map[string]string) *atomDiff {
+	keys := make([]string, 0, len(aProps)+len(bProps))
+	for k := range aProps {
+		keys = append(keys, k)
+	}
+	for k := range bProps {
+		if _, ok := aProps[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		field := "properties/" + pathSegmentEscaper.Replace(k)
+		aValue, aOk := aProps[k]
+		bValue, bOk := bProps[k]
+		switch {
+		case !aOk:
+			return &atomDiff{field: field, detail: fmt.Sprintf("missing in a, %q in b", bValue)}
+		case !bOk:
+			return &atomDiff{field: field, detail: fmt.Sprintf("%q in a, missing in b", aValue)}
+		case aValue != bValue:
+			return &atomDiff{field: field, detail: fmt.Sprintf("%q != %q", aValue, bValue)}
+		}
+	}
+	return nil
+}
+
+// joinPath appends a segment to a GetByPath-style path.
+func joinPath(path, segment string) string {
+	if path == "" {
+		return segment
+	}
+	return path + "/" + segment
+}
@@ -0,0 +1,50 @@
+package omni
+
+import "testing"
+
+func TestExplainDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(b *Atom)
+		want   string
+	}{
+		{"equal", func(b *Atom) {}, ""},
+		{"root id", func(b *Atom) { b.SetID("p2") }, `at id: "p1" != "p2"`},
+		{"child type", func(b *Atom) { b.ChildrenGet()[0].SetType("image") }, `at children/0/type: "section" != "image"`},
+		{"changed property", func(b *Atom) {
+			b.ChildrenGet()[0].ChildrenGet()[0].Set("content", "Bye")
+		}, `at children/0/children/0/properties/content: "Hello" != "Bye"`},
+		{"missing property", func(b *Atom) { b.Remove("a/b") }, `at properties/a~1b: "x" in a, missing in b`},
+		{"extra property", func(b *Atom) { b.Set("lang", "en") }, `at properties/lang: missing in a, "en" in b`},
+		{"extra child", func(b *Atom) { b.ChildAdd(NewAtom("footer", WithID("f1"))) }, `at root: 2 children != 3 children`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestTree()
+			b := newTestTree()
+			tt.mutate(b)
+
+			if got := ExplainDiff(a, b); got != tt.want {
+				t.Fatalf("ExplainDiff() = %q, want %q", got, tt.want)
+			}
+			if (tt.want == "") != a.Equals(b) {
+				t.Fatalf("ExplainDiff() disagrees with Equals")
+			}
+		})
+	}
+}
+
+func TestExplainDiff_Nil(t *testing.T) {
+	atom := NewAtom("page", WithID("p1"))
+
+	if got := ExplainDiff(nil, nil); got != "" {
+		t.Fatalf("ExplainDiff(nil, nil) = %q, want empty", got)
+	}
+	if got := ExplainDiff(nil, atom); got != "at root: a is nil, b is not" {
+		t.Fatalf("ExplainDiff(nil, atom) = %q", got)
+	}
+	if got := ExplainDiff(atom, nil); got != "at root: b is nil, a is not" {
+		t.Fatalf("ExplainDiff(atom, nil) = %q", got)
+	}
+}
@@ -1,6 +1,6 @@
 package omni
 
-// FindAtomByType recursively finds the first atom with the given type in a tree.
+// FindFirstAtomByType recursively finds the first atom with the given type in a tree.
 // It performs a pre-order traversal: checks the current node first, then its children in order.
 func FindFirstAtomByType(root AtomInterface, atomType string) AtomInterface {
 	if root == nil {
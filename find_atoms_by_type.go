@@ -20,3 +20,67 @@ func FindAtomsByType(root AtomInterface, atomType string) []AtomInterface {
 
 	return result
 }
+
+// FindAtomsByTypeBudget finds atoms of a specific type like FindAtomsByType,
+// but visits at most maxVisit atoms. This bounds the work done on untrusted,
+// enormous or cyclic trees.
+//
+// It returns the matches found within the budget (in pre-order) and whether
+// the budget was exceeded, i.e. the traversal stopped before visiting every atom.
+func FindAtomsByTypeBudget(root AtomInterface, atomType string, maxVisit int) ([]AtomInterface, bool) {
+	result := []AtomInterface{}
+	if root == nil {
+		return result, false
+	}
+
+	visited := 0
+	stack := []AtomInterface{root}
+	for len(stack) > 0 {
+		if visited >= maxVisit {
+			return result, true
+		}
+
+		atom := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		visited++
+
+		if atom.GetType() == atomType {
+			result = append(result, atom)
+		}
+
+		// Push children in reverse so they are visited in order (pre-order)
+		children := atom.ChildrenGet()
+		for i := len(children) - 1; i >= 0; i-- {
+			if children[i] != nil {
+				stack = append(stack, children[i])
+			}
+		}
+	}
+
+	return result, false
+}
+
+// FindAtomsByTypeDepth finds atoms of a specific type like FindAtomsByType,
+// but descends at most maxDepth levels below root: 0 checks only the root,
+// 1 the root and its immediate children, and so on. A negative maxDepth
+// returns no matches. Matches are returned in pre-order.
+func FindAtomsByTypeDepth(root AtomInterface, atomType string, maxDepth int) []AtomInterface {
+	result := []AtomInterface{}
+	if root == nil || maxDepth < 0 {
+		return result
+	}
+
+	if root.GetType() == atomType {
+		result = append(result, root)
+	}
+
+	if maxDepth == 0 {
+		return result
+	}
+
+	for _, child := range root.ChildrenGet() {
+		result = append(result, FindAtomsByTypeDepth(child, atomType, maxDepth-1)...)
+	}
+
+	return result
+}
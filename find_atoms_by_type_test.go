@@ -56,3 +56,88 @@ func TestFindAtomsByType_RootOnly(t *testing.T) {
 		t.Fatalf("expected root to be the match, got %q", matches[0].GetID())
 	}
 }
+
+func TestFindAtomsByTypeBudget_WithinBudget(t *testing.T) {
+	root := NewAtom("item", WithID("root"))
+	a := NewAtom("item", WithID("a"))
+	a.ChildAdd(NewAtom("item", WithID("a1")))
+	root.ChildAdd(a)
+	root.ChildAdd(NewAtom("other", WithID("b")))
+
+	got, exceeded := FindAtomsByTypeBudget(root, "item", 10)
+	if exceeded {
+		t.Fatal("expected budget not to be exceeded")
+	}
+	want := []string{"root", "a", "a1"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d matches, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i].GetID() != want[i] {
+			t.Fatalf("match %d: expected %q, got %q", i, want[i], got[i].GetID())
+		}
+	}
+}
+
+func TestFindAtomsByTypeBudget_StopsWhenExceeded(t *testing.T) {
+	root := NewAtom("item", WithID("root"))
+	for i := 0; i < 10; i++ {
+		root.ChildAdd(NewAtom("item"))
+	}
+
+	got, exceeded := FindAtomsByTypeBudget(root, "item", 3)
+	if !exceeded {
+		t.Fatal("expected budget to be exceeded")
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 matches within budget, got %d", len(got))
+	}
+}
+
+func TestFindAtomsByTypeBudget_StopsOnCycle(t *testing.T) {
+	root := NewAtom("item", WithID("root"))
+	root.ChildAdd(root)
+
+	got, exceeded := FindAtomsByTypeBudget(root, "item", 5)
+	if !exceeded || len(got) != 5 {
+		t.Fatalf("expected truncated traversal of cyclic tree, got %d matches exceeded=%v", len(got), exceeded)
+	}
+}
+
+func TestFindAtomsByTypeDepth(t *testing.T) {
+	// root(target) -> a(node) -> a1(target) -> a1x(target)
+	//              -> b(target)
+	root := NewAtom("target", WithID("root"))
+	a := NewAtom("node", WithID("a"))
+	a1 := NewAtom("target", WithID("a1"))
+	a1.ChildAdd(NewAtom("target", WithID("a1x")))
+	a.ChildAdd(a1)
+	root.ChildrenSet([]AtomInterface{a, NewAtom("target", WithID("b"))})
+
+	tests := []struct {
+		maxDepth int
+		want     []string
+	}{
+		{-1, []string{}},
+		{0, []string{"root"}},
+		{1, []string{"root", "b"}},
+		{2, []string{"root", "a1", "b"}},
+		{10, []string{"root", "a1", "a1x", "b"}},
+	}
+
+	for _, tt := range tests {
+		matches := FindAtomsByTypeDepth(root, "target", tt.maxDepth)
+		if len(matches) != len(tt.want) {
+			t.Fatalf("maxDepth %d: got %d matches, want %v", tt.maxDepth, len(matches), tt.want)
+		}
+		for i, id := range tt.want {
+			if matches[i].GetID() != id {
+				t.Fatalf("maxDepth %d: match %d = %s, want %s", tt.maxDepth, i, matches[i].GetID(), id)
+			}
+		}
+	}
+
+	if got := FindAtomsByTypeDepth(nil, "target", 5); len(got) != 0 {
+		t.Fatalf("expected no matches for nil root, got %d", len(got))
+	}
+}
@@ -7,9 +7,20 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 )
 
+// DefaultMaxDepth is the maximum nesting depth of atoms accepted by MapToAtom
+// and GobToAtom (the root being level 1). It protects against deeply nested,
+// untrusted payloads exhausting the stack during deserialization.
+const DefaultMaxDepth = 1000
+
+// MaxAtomDataSize is the maximum size in bytes of the encoded data of a
+// single top-level atom accepted by GobToAtoms and JSONToAtomsLimited,
+// and the default limit of DecodeAtomsWithLimit.
+const MaxAtomDataSize = 10 * 1024 * 1024 // 10MB
+
 // AtomsToJSON converts a slice of AtomInterface to a JSON string.
 //
 // Business logic:
@@ -25,6 +36,38 @@ func AtomsToJSON(atoms []AtomInterface) (string, error) {
 		return "[]", nil
 	}
 
+	atomsJSON, err := json.Marshal(nonNilAtomMaps(atoms))
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal atoms to JSON: %w", err)
+	}
+
+	return string(atomsJSON), nil
+}
+
+// AtomsToJSONPretty converts a slice of AtomInterface to an indented JSON string.
+//
+// Business logic:
+// - Same as AtomsToJSON, but indents the output with two spaces, like ToJSONPretty
+// - Handles nil input by returning an empty array JSON string
+//
+// Returns:
+// - string: indented JSON-encoded array of atoms
+// - error: if marshaling to JSON fails
+func AtomsToJSONPretty(atoms []AtomInterface) (string, error) {
+	if atoms == nil {
+		return "[]", nil
+	}
+
+	atomsJSON, err := json.MarshalIndent(nonNilAtomMaps(atoms), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal atoms to pretty JSON: %w", err)
+	}
+
+	return string(atomsJSON), nil
+}
+
+// nonNilAtomMaps converts the non-nil atoms to maps using ToMap().
+func nonNilAtomMaps(atoms []AtomInterface) []map[string]any {
 	atomsMaps := make([]map[string]any, 0, len(atoms))
 
 	for _, atom := range atoms {
@@ -33,12 +76,7 @@ func AtomsToJSON(atoms []AtomInterface) (string, error) {
 		}
 	}
 
-	atomsJSON, err := json.Marshal(atomsMaps)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal atoms to JSON: %w", err)
-	}
-
-	return string(atomsJSON), nil
+	return atomsMaps
 }
 
 // JSONToAtom converts a JSON string to a single Atom.
@@ -179,6 +217,14 @@ func AtomsToMap(atoms []AtomInterface) []map[string]any {
 // Properties should be in a nested "properties" map, and children in a "children" slice.
 // For backward compatibility, top-level properties are also supported but not recommended.
 //
+// Properties remain string-typed: scalar values are converted to their string form,
+// while complex values (nested objects and arrays) are stored as JSON text,
+// so they can be parsed again with json.Unmarshal.
+//
+// Children are nested at most DefaultMaxDepth levels deep (the root being
+// level 1); deeper nesting returns an error instead of recursing further.
+// Use MapToAtomWithMaxDepth to choose a different limit.
+//
 // Parameters:
 //   - atomMap: map containing the atom data
 //
@@ -186,6 +232,34 @@ func AtomsToMap(atoms []AtomInterface) []map[string]any {
 //   - AtomInterface: the converted atom
 //   - error: if the map is not a valid atom
 func MapToAtom(atomMap map[string]any) (AtomInterface, error) {
+	return MapToAtomWithMaxDepth(atomMap, DefaultMaxDepth)
+}
+
+// MapToAtomWithMaxDepth is like MapToAtom, but allows atoms to be nested
+// at most maxDepth levels deep (the root being level 1).
+// A non-positive maxDepth uses DefaultMaxDepth.
+//
+// Parameters:
+//   - atomMap: map containing the atom data
+//   - maxDepth: the maximum nesting depth
+//
+// Returns:
+//   - AtomInterface: the converted atom
+//   - error: if the map is not a valid atom or is nested too deeply
+func MapToAtomWithMaxDepth(atomMap map[string]any, maxDepth int) (AtomInterface, error) {
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	return mapToAtom(atomMap, 1, maxDepth)
+}
+
+// mapToAtom converts the atom map found at the given depth,
+// recursing into its children up to maxDepth.
+func mapToAtom(atomMap map[string]any, depth, maxDepth int) (AtomInterface, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("atom nesting exceeds max depth %d", maxDepth)
+	}
+
 	if atomMap == nil {
 		return nil, errors.New("atom map cannot be nil")
 	}
@@ -197,8 +271,9 @@ func MapToAtom(atomMap map[string]any) (AtomInterface, error) {
 	}
 
 	// Extract type and id from top-level fields
-	atomType, _ := atomMapCopy["type"].(string)
-	id, _ := atomMapCopy["id"].(string)
+	// Numeric and boolean values are coerced to their string form
+	atomType, _ := scalarToString(atomMapCopy["type"])
+	id, _ := scalarToString(atomMapCopy["id"])
 
 	// Check for required fields first
 	if atomType == "" {
@@ -215,33 +290,14 @@ func MapToAtom(atomMap map[string]any) (AtomInterface, error) {
 	props := make(map[string]string)
 	if propsMap, ok := atomMapCopy["properties"].(map[string]any); ok && len(propsMap) > 0 {
 		for k, v := range propsMap {
-			// Convert value to string
-			var strVal string
-			switch v := v.(type) {
-			case string:
-				strVal = v
-			case fmt.Stringer:
-				strVal = v.String()
-			default:
-				strVal = fmt.Sprintf("%v", v)
-			}
-			props[k] = strVal
+			props[k] = propertyValueToString(v)
 		}
 	}
 
 	// For backward compatibility, also check for top-level properties
 	for k, v := range atomMapCopy {
 		if k != "id" && k != "type" && k != "properties" && k != "children" {
-			var strVal string
-			switch v := v.(type) {
-			case string:
-				strVal = v
-			case fmt.Stringer:
-				strVal = v.String()
-			default:
-				strVal = fmt.Sprintf("%v", v)
-			}
-			props[k] = strVal
+			props[k] = propertyValueToString(v)
 		}
 	}
 
@@ -254,12 +310,12 @@ func MapToAtom(atomMap map[string]any) (AtomInterface, error) {
 	if children, ok := atomMapCopy["children"].([]any); ok && len(children) > 0 {
 		for _, child := range children {
 			if childMap, ok := child.(map[string]any); ok {
-				childAtom, err := MapToAtom(childMap)
+				childAtom, err := mapToAtom(childMap, depth+1, maxDepth)
 				if err != nil {
 					return nil, fmt.Errorf("failed to create child atom: %w", err)
 				}
-				// Add child and update the atom reference
-				atom = atom.ChildAdd(childAtom).(*Atom)
+				// ChildAdd mutates the atom in place
+				atom.ChildAdd(childAtom)
 			}
 		}
 	}
@@ -387,16 +443,70 @@ func AtomsToGob(atoms []AtomInterface) ([]byte, error) {
 // - Decodes the count of atoms first
 // - Then decodes each atom's data and validates it before conversion
 // - Preserves the order of atoms from the encoded data
+// - Preserves nil entries written by AtomsToGob, so the result can be
+//   passed back to AtomsToGob unchanged
+// - Rejects atoms whose encoded data is larger than MaxAtomDataSize;
+//   use DecodeAtomsWithLimit to choose a different limit
 //
 // Parameters:
 //   - data: binary data containing gob-encoded atoms
 //
 // Returns:
-//   - []AtomInterface: slice of decoded atoms
+//   - []AtomInterface: slice of decoded atoms (may contain nils)
 //   - error: if the data cannot be decoded or is invalid
 func GobToAtoms(data []byte) ([]AtomInterface, error) {
+	return DecodeAtomsWithLimit(data, MaxAtomDataSize)
+}
+
+// DecodeAtomsWithLimit is like GobToAtoms, but rejects atoms whose encoded
+// data is larger than maxPerAtom bytes instead of MaxAtomDataSize.
+//
+// Tradeoff: the data of each atom is allocated in full before it is decoded,
+// so the limit bounds the memory a single atom from untrusted input can claim.
+// Raise it for trusted data with large atoms (such as embedded images), and
+// lower it for services exposed to untrusted input.
+// A non-positive maxPerAtom uses MaxAtomDataSize.
+//
+// Parameters:
+//   - data: binary data containing gob-encoded atoms
+//   - maxPerAtom: the maximum encoded size of a single atom, in bytes
+//
+// Returns:
+//   - []AtomInterface: slice of decoded atoms (may contain nils)
+//   - error: if the data cannot be decoded, is invalid or exceeds the limit
+func DecodeAtomsWithLimit(data []byte, maxPerAtom int) ([]AtomInterface, error) {
+	if maxPerAtom <= 0 {
+		maxPerAtom = MaxAtomDataSize
+	}
+
+	result, err := decodeAtoms(data, maxPerAtom)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DecodeAtomsPartial is like GobToAtoms, but when decoding fails, such as on
+// a file truncated mid-write, it returns the atoms decoded before the failure
+// together with an error describing where decoding stopped. Use it to salvage
+// what can be recovered; use GobToAtoms for all-or-nothing decoding.
+//
+// Parameters:
+//   - data: binary data containing gob-encoded atoms
+//
+// Returns:
+//   - []AtomInterface: the atoms decoded before any failure (may contain nils)
+//   - error: if the data could not be decoded completely
+func DecodeAtomsPartial(data []byte) ([]AtomInterface, error) {
+	return decodeAtoms(data, MaxAtomDataSize)
+}
+
+// decodeAtoms decodes atoms written by AtomsToGob, rejecting atoms larger
+// than maxPerAtom bytes. On error it returns the atoms decoded so far.
+func decodeAtoms(data []byte, maxPerAtom int) ([]AtomInterface, error) {
+	result := []AtomInterface{}
 	if len(data) == 0 {
-		return []AtomInterface{}, nil
+		return result, nil
 	}
 
 	buffer := bytes.NewBuffer(data)
@@ -405,22 +515,20 @@ func GobToAtoms(data []byte) ([]AtomInterface, error) {
 	// First decode the number of atoms
 	var count int
 	if err := decoder.Decode(&count); err != nil {
-		return nil, fmt.Errorf("failed to decode atom count: %w", err)
+		return result, fmt.Errorf("failed to decode atom count: %w", err)
 	}
 
 	// Validate count is reasonable
 	if count < 0 {
-		return nil, fmt.Errorf("invalid atom count: %d", count)
+		return result, fmt.Errorf("invalid atom count: %d", count)
 	}
 
-	result := make([]AtomInterface, 0, count)
-
 	// Then decode each atom
 	for i := 0; i < count; i++ {
 		// Decode the nil marker
 		var isPresent bool
 		if err := decoder.Decode(&isPresent); err != nil {
-			return nil, fmt.Errorf("failed to decode nil marker for atom %d: %w", i, err)
+			return result, fmt.Errorf("failed to decode nil marker for atom %d: %w", i, err)
 		}
 
 		if !isPresent {
@@ -431,25 +539,28 @@ func GobToAtoms(data []byte) ([]AtomInterface, error) {
 		// Decode the atom data length
 		var dataLen int
 		if err := decoder.Decode(&dataLen); err != nil {
-			return nil, fmt.Errorf("failed to decode data length for atom %d: %w", i, err)
+			return result, fmt.Errorf("failed to decode data length for atom %d: %w", i, err)
 		}
 
 		// Validate data length is reasonable
-		if dataLen < 0 || dataLen > 10*1024*1024 { // 10MB max per atom
-			return nil, fmt.Errorf("invalid data length %d for atom %d", dataLen, i)
+		if dataLen < 0 {
+			return result, fmt.Errorf("invalid data length %d for atom %d", dataLen, i)
+		}
+		if dataLen > maxPerAtom {
+			return result, fmt.Errorf("data length %d for atom %d exceeds limit %d", dataLen, i, maxPerAtom)
 		}
 
 		// Read the atom data
 		atomData := make([]byte, dataLen)
 		if _, err := io.ReadFull(buffer, atomData); err != nil {
-			return nil, fmt.Errorf("failed to read atom %d data: %w", i, err)
+			return result, fmt.Errorf("failed to read atom %d data: %w", i, err)
 		}
 
 		// Validate the atom data before creating the atom
 		// Create the atom from the gob data
 		atom, err := GobToAtom(atomData)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create atom %d from gob: %w", i, err)
+			return result, fmt.Errorf("failed to create atom %d from gob: %w", i, err)
 		}
 
 		result = append(result, atom)
@@ -465,6 +576,8 @@ func GobToAtoms(data []byte) ([]AtomInterface, error) {
 // - Creates a new atom with the decoded type, id, and properties
 // - Recursively decodes and adds child atoms
 // - Returns an error if the data is invalid
+// - Returns an error if atoms are nested more than DefaultMaxDepth levels
+//   deep; use GobToAtomWithMaxDepth to choose a different limit
 //
 // Parameters:
 //   - data: binary data containing the gob-encoded atom
@@ -473,11 +586,36 @@ func GobToAtoms(data []byte) ([]AtomInterface, error) {
 //   - AtomInterface: the decoded atom
 //   - error: if decoding fails
 func GobToAtom(data []byte) (AtomInterface, error) {
-	// Validate the input data first
-	if valid, err := isValidAtomGob(data); !valid {
+	return GobToAtomWithMaxDepth(data, DefaultMaxDepth)
+}
+
+// GobToAtomWithMaxDepth is like GobToAtom, but allows atoms to be nested
+// at most maxDepth levels deep (the root being level 1).
+// A non-positive maxDepth uses DefaultMaxDepth.
+//
+// Parameters:
+//   - data: binary data containing the gob-encoded atom
+//   - maxDepth: the maximum nesting depth
+//
+// Returns:
+//   - AtomInterface: the decoded atom
+//   - error: if decoding fails or the atom is nested too deeply
+func GobToAtomWithMaxDepth(data []byte, maxDepth int) (AtomInterface, error) {
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+
+	// Validate the whole tree once, before creating any atom
+	if valid, err := isValidAtomGob(data, 1, maxDepth); !valid {
 		return nil, fmt.Errorf("invalid gob data: %w", err)
 	}
 
+	return gobToAtom(data)
+}
+
+// gobToAtom decodes an atom and its children from gob data
+// that has already been validated by isValidAtomGob.
+func gobToAtom(data []byte) (AtomInterface, error) {
 	// Create a temporary struct for decoding
 	var temp struct {
 		ID         string
@@ -486,10 +624,6 @@ func GobToAtom(data []byte) (AtomInterface, error) {
 		Children   [][]byte
 	}
 
-	// Register the type
-	gob.Register(&Atom{})
-
-
 	// Decode the data (we know it's valid from the validation above)
 	decoder := gob.NewDecoder(bytes.NewReader(data))
 	if err := decoder.Decode(&temp); err != nil {
@@ -506,17 +640,36 @@ func GobToAtom(data []byte) (AtomInterface, error) {
 
 	// Recursively decode children
 	for _, childData := range temp.Children {
-		child, err := GobToAtom(childData)
+		child, err := gobToAtom(childData)
 		if err != nil {
 			return nil, fmt.Errorf("failed to decode child: %w", err)
 		}
-		// Use ChildAdd to add the child
-		atom = atom.ChildAdd(child)
+		// ChildAdd mutates the atom in place
+		atom.ChildAdd(child)
 	}
 
 	return atom, nil
 }
 
+// DecodeAtomOrNil is a lenient variant of GobToAtom.
+//
+// Business logic:
+// - Returns (nil, nil) for empty or nil input, treated as "no atom"
+// - Otherwise behaves exactly like GobToAtom
+//
+// Parameters:
+//   - data: binary data containing the gob-encoded atom, or empty for no atom
+//
+// Returns:
+//   - AtomInterface: the decoded atom, or nil if data is empty
+//   - error: if decoding non-empty data fails
+func DecodeAtomOrNil(data []byte) (AtomInterface, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return GobToAtom(data)
+}
+
 // isValidAtomGob validates that the given data is a valid gob-encoded atom.
 //
 // Business logic:
@@ -524,14 +677,21 @@ func GobToAtom(data []byte) (AtomInterface, error) {
 // - Attempts to decode the data into a temporary struct
 // - Validates the presence of required fields (id, type)
 // - Recursively validates child atoms
+// - Rejects atoms nested deeper than maxDepth
 //
 // Parameters:
 //   - data: binary data to validate
+//   - depth: the nesting depth of data, 1 for the root
+//   - maxDepth: the maximum nesting depth
 //
 // Returns:
 //   - bool: true if the data is valid
 //   - error: description of the validation failure if invalid
-func isValidAtomGob(data []byte) (bool, error) {
+func isValidAtomGob(data []byte, depth, maxDepth int) (bool, error) {
+	if depth > maxDepth {
+		return false, fmt.Errorf("atom nesting exceeds max depth %d", maxDepth)
+	}
+
 	if len(data) == 0 {
 		return false, errors.New("cannot validate empty data")
 	}
@@ -564,7 +724,7 @@ func isValidAtomGob(data []byte) (bool, error) {
 
 	// Recursively validate children
 	for i, childData := range temp.Children {
-		if valid, err := isValidAtomGob(childData); !valid {
+		if valid, err := isValidAtomGob(childData, depth+1, maxDepth); !valid {
 			return false, fmt.Errorf("invalid child at index %d: %v", i, err)
 		}
 	}
@@ -576,9 +736,9 @@ func isValidAtomGob(data []byte) (bool, error) {
 //
 // Business logic:
 // - checks if the JSON string is empty or "null" (returns false)
-// - checks basic JSON structure (starts with '{' or '[' and ends with '}' or ']')
-// - for objects, checks if it contains required "id" and "type" fields
-// - for arrays, checks if it's empty or contains valid objects
+// - parses the JSON and checks that the root is an object or an array
+// - for objects, checks that the top-level "id" and "type" keys are present
+// - for arrays, elements are validated during conversion
 //
 // Returns:
 // - true, nil if the JSON string is a valid Atom JSON string
@@ -588,40 +748,34 @@ func isValidAtomJSON(jsonString string) (bool, error) {
 		return false, errors.New("JSON string cannot be empty or 'null'")
 	}
 
-	// Check basic JSON structure
-	isObject := strings.HasPrefix(jsonString, "{") && strings.HasSuffix(jsonString, "}")
-	isArray := strings.HasPrefix(jsonString, "[") && strings.HasSuffix(jsonString, "]")
-
-	if !isObject && !isArray {
-		return false, errors.New("JSON must be an object or array")
+	var parsed any
+	if err := json.Unmarshal([]byte(jsonString), &parsed); err != nil {
+		return false, fmt.Errorf("malformed JSON: %w", err)
 	}
 
-	// If it's an array, it's valid if it's empty or contains valid objects
-	if isArray {
-		// Empty array is valid
-		if jsonString == "[]" {
-			return true, nil
-		}
-		// For non-empty arrays, we'll validate each element during parsing
+	switch root := parsed.(type) {
+	case []any:
+		// Array elements are validated during conversion
 		return true, nil
-	}
-
-	// For single objects, check required fields
-	hasID := strings.Contains(jsonString, `"id"`)
-	hasType := strings.Contains(jsonString, `"type"`)
-
-	if !hasID || !hasType {
-		missing := []string{}
-		if !hasID {
-			missing = append(missing, "id")
-		}
-		if !hasType {
-			missing = append(missing, "type")
+	case map[string]any:
+		_, hasID := root["id"]
+		_, hasType := root["type"]
+
+		if !hasID || !hasType {
+			missing := []string{}
+			if !hasID {
+				missing = append(missing, "id")
+			}
+			if !hasType {
+				missing = append(missing, "type")
+			}
+			return false, fmt.Errorf("missing required fields: %v", strings.Join(missing, ", "))
 		}
-		return false, fmt.Errorf("missing required fields: %v", strings.Join(missing, ", "))
-	}
 
-	return true, nil
+		return true, nil
+	default:
+		return false, errors.New("JSON must be an object or array")
+	}
 }
 
 // isValidAtomMap validates that a map represents a valid atom structure.
@@ -644,14 +798,14 @@ func isValidAtomMap(atomMap map[string]any) (bool, error) {
 	}
 
 	// Check required fields
-	id, idOk := atomMap["id"].(string)
+	id, idOk := scalarToString(atomMap["id"])
 	if !idOk || id == "" {
-		return false, errors.New("atom map must contain a non-empty string 'id' field")
+		return false, errors.New("atom map must contain a non-empty string or numeric 'id' field")
 	}
 
-	typeStr, typeOk := atomMap["type"].(string)
+	typeStr, typeOk := scalarToString(atomMap["type"])
 	if !typeOk || typeStr == "" {
-		return false, errors.New("atom map must contain a non-empty string 'type' field")
+		return false, errors.New("atom map must contain a non-empty string or numeric 'type' field")
 	}
 
 	// Check for invalid top-level keys (only id, type, properties, children are allowed)
@@ -676,8 +830,9 @@ func isValidAtomMap(atomMap map[string]any) (bool, error) {
 		for propKey, propValue := range propsMap {
 			switch propValue.(type) {
 			case string, fmt.Stringer, int, int8, int16, int32, int64,
-				uint, uint8, uint16, uint32, uint64, float32, float64, bool:
-				// These types can be converted to strings
+				uint, uint8, uint16, uint32, uint64, float32, float64, bool,
+				map[string]any, []any:
+				// These types can be converted to strings (complex values as JSON text)
 				continue
 			default:
 				return false, fmt.Errorf("property '%s' in properties map has invalid type %T, must be string or convertible to string", propKey, propValue)
@@ -710,3 +865,61 @@ func isValidAtomMap(atomMap map[string]any) (bool, error) {
 
 	return true, nil
 }
+
+// scalarToString converts a scalar value (string, number or boolean)
+// to its string form, as used for the top-level "id" and "type" fields.
+//
+// Business logic:
+// - Strings are returned as is
+// - Floats are formatted without exponent or trailing zeros (123.0 -> "123")
+// - Integers, json.Number and booleans use their natural string form
+// - Any other type (including nil) is not convertible
+//
+// Returns:
+//   - string: the string form of the value
+//   - bool: true if the value could be converted
+func scalarToString(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	case json.Number:
+		return v.String(), true
+	case int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, bool:
+		return fmt.Sprintf("%v", v), true
+	default:
+		return "", false
+	}
+}
+
+// propertyValueToString converts a property value from a map (e.g. decoded JSON)
+// to the string stored on the atom.
+//
+// Business logic:
+// - Strings are returned as is
+// - Nested objects and arrays are encoded as JSON text
+// - Numbers and booleans use their canonical string form (see scalarToString)
+// - fmt.Stringer values use their String method
+// - Any other value is formatted with fmt's %v verb
+func propertyValueToString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]any, []any:
+		if jsonData, err := json.Marshal(v); err == nil {
+			return string(jsonData)
+		}
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	if str, ok := scalarToString(value); ok {
+		return str
+	}
+
+	return fmt.Sprintf("%v", value)
+}
@@ -74,3 +74,24 @@ func TestIsValidAtomMap_InvalidChildrenType(t *testing.T) {
 		t.Fatalf("expected invalid due to children type, got ok=%v err=%v", ok, err)
 	}
 }
+
+func TestIsValidAtomJSON_KeysOnlyInsidePropertyValue_ReturnsError(t *testing.T) {
+	ok, err := isValidAtomJSON(`{"properties":{"description":"the "}, "note":"\"id\" and \"type\""}`)
+	if ok || err == nil {
+		t.Fatalf("expected invalid when id/type only appear inside values, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestIsValidAtomJSON_MalformedObject_ReturnsError(t *testing.T) {
+	ok, err := isValidAtomJSON(`{"id":"a","type":"b",}`)
+	if ok || err == nil {
+		t.Fatalf("expected invalid for malformed JSON, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestIsValidAtomJSON_ValidObject(t *testing.T) {
+	ok, err := isValidAtomJSON(`{"id":"a","type":"b"}`)
+	if !ok || err != nil {
+		t.Fatalf("expected valid object, got ok=%v err=%v", ok, err)
+	}
+}
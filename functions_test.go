@@ -1,6 +1,9 @@
 package omni_test
 
 import (
+	"encoding/json"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -176,26 +179,7 @@ func TestAtomsToMap(t *testing.T) {
 	}
 
 	// Verify second atom's children
-	// Print the actual type and value for debugging
-	t.Logf("maps[1][\"children\"] type: %T, value: %+v", maps[1]["children"], maps[1]["children"])
-
-	// Try different type assertions
-	var children []interface{}
-	var ok bool
-
-	// First try as []interface{}
-	children, ok = maps[1]["children"].([]interface{})
-	if !ok {
-		// Then try as []map[string]interface{}
-		if childrenMaps, mapOk := maps[1]["children"].([]map[string]interface{}); mapOk {
-			children = make([]interface{}, len(childrenMaps))
-			for i, m := range childrenMaps {
-				children[i] = m
-			}
-			ok = true
-		}
-	}
-
+	children, ok := maps[1]["children"].([]any)
 	if !ok || len(children) != 1 {
 		t.Fatalf("maps[1][\"children\"] is not a slice or has wrong length: %v, want 1", maps[1]["children"])
 	}
@@ -422,3 +406,420 @@ func TestJSONToAtom_ValidObject(t *testing.T) {
         t.Fatalf("atom.Get(\"k\") = %v, want v", val)
     }
 }
+
+func TestMapToAtom_ChildrenAttachedInOrder(t *testing.T) {
+	atomMap := map[string]any{
+		"id":   "parent",
+		"type": "container",
+		"children": []any{
+			map[string]any{"id": "c1", "type": "item"},
+			map[string]any{"id": "c2", "type": "item"},
+			map[string]any{"id": "c3", "type": "item"},
+		},
+	}
+
+	atom, err := omni.MapToAtom(atomMap)
+	if err != nil {
+		t.Fatalf("MapToAtom() error = %v", err)
+	}
+
+	children := atom.ChildrenGet()
+	if len(children) != 3 {
+		t.Fatalf("expected 3 children, got %d", len(children))
+	}
+	for i, want := range []string{"c1", "c2", "c3"} {
+		if got := children[i].GetID(); got != want {
+			t.Fatalf("child %d: got %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestGobToAtom_ChildrenAttachedInOrder(t *testing.T) {
+	parent := omni.NewAtom("container", omni.WithID("parent"))
+	parent.ChildAdd(omni.NewAtom("item", omni.WithID("c1")))
+	parent.ChildAdd(omni.NewAtom("item", omni.WithID("c2")))
+	parent.ChildAdd(omni.NewAtom("item", omni.WithID("c3")))
+
+	data, err := parent.ToGob()
+	if err != nil {
+		t.Fatalf("ToGob() error = %v", err)
+	}
+
+	atom, err := omni.GobToAtom(data)
+	if err != nil {
+		t.Fatalf("GobToAtom() error = %v", err)
+	}
+
+	children := atom.ChildrenGet()
+	if len(children) != 3 {
+		t.Fatalf("expected 3 children, got %d", len(children))
+	}
+	for i, want := range []string{"c1", "c2", "c3"} {
+		if got := children[i].GetID(); got != want {
+			t.Fatalf("child %d: got %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestDecodeAtomOrNil_EmptyInput(t *testing.T) {
+	for _, data := range [][]byte{nil, {}} {
+		atom, err := omni.DecodeAtomOrNil(data)
+		if err != nil {
+			t.Fatalf("DecodeAtomOrNil(%v) error = %v", data, err)
+		}
+		if atom != nil {
+			t.Fatalf("DecodeAtomOrNil(%v) = %v, want nil", data, atom)
+		}
+	}
+}
+
+func TestDecodeAtomOrNil_ValidAndInvalidData(t *testing.T) {
+	data, err := omni.NewAtom("type1", omni.WithID("id1")).ToGob()
+	if err != nil {
+		t.Fatalf("ToGob() error = %v", err)
+	}
+
+	atom, err := omni.DecodeAtomOrNil(data)
+	if err != nil {
+		t.Fatalf("DecodeAtomOrNil() error = %v", err)
+	}
+	if atom == nil || atom.GetID() != "id1" {
+		t.Fatalf("DecodeAtomOrNil() = %v, want atom id1", atom)
+	}
+
+	if _, err := omni.DecodeAtomOrNil([]byte("garbage")); err == nil {
+		t.Fatal("DecodeAtomOrNil() should return error for invalid data")
+	}
+
+	if _, err := omni.GobToAtom(nil); err == nil {
+		t.Fatal("GobToAtom() should remain strict for empty data")
+	}
+}
+
+func TestJSONToAtoms_NumericIDsAreCoerced(t *testing.T) {
+	atoms, err := omni.JSONToAtoms(`[{"id":123,"type":"item"},{"id":1.5,"type":"item"}]`)
+	if err != nil {
+		t.Fatalf("JSONToAtoms() error = %v", err)
+	}
+	if len(atoms) != 2 {
+		t.Fatalf("expected 2 atoms, got %d", len(atoms))
+	}
+	if got := atoms[0].GetID(); got != "123" {
+		t.Fatalf("atoms[0].GetID() = %q, want 123", got)
+	}
+	if got := atoms[1].GetID(); got != "1.5" {
+		t.Fatalf("atoms[1].GetID() = %q, want 1.5", got)
+	}
+}
+
+func TestMapToAtom_NumericAndBoolIDAndType(t *testing.T) {
+	atom, err := omni.MapToAtom(map[string]any{"id": float64(42), "type": true})
+	if err != nil {
+		t.Fatalf("MapToAtom() error = %v", err)
+	}
+	if atom.GetID() != "42" || atom.GetType() != "true" {
+		t.Fatalf("unexpected id/type: %q/%q", atom.GetID(), atom.GetType())
+	}
+
+	atoms := omni.MapToAtoms([]map[string]any{{"id": 7, "type": "item"}})
+	if len(atoms) != 1 || atoms[0].GetID() != "7" {
+		t.Fatalf("MapToAtoms() did not coerce numeric id: %v", atoms)
+	}
+}
+
+func TestGobToAtoms_RoundTripPreservesNils(t *testing.T) {
+	atoms := []omni.AtomInterface{
+		omni.NewAtom("item", omni.WithID("a1")),
+		nil,
+		omni.NewAtom("item", omni.WithID("a2")),
+	}
+
+	data, err := omni.AtomsToGob(atoms)
+	if err != nil {
+		t.Fatalf("AtomsToGob() error = %v", err)
+	}
+
+	decoded, err := omni.GobToAtoms(data)
+	if err != nil {
+		t.Fatalf("GobToAtoms() error = %v", err)
+	}
+	if len(decoded) != 3 || decoded[1] != nil {
+		t.Fatalf("expected 3 atoms with a nil in the middle, got %v", decoded)
+	}
+	if decoded[0].GetID() != "a1" || decoded[2].GetID() != "a2" {
+		t.Fatalf("unexpected decoded ids: %s, %s", decoded[0].GetID(), decoded[2].GetID())
+	}
+
+	// The result composes directly with AtomsToGob
+	again, err := omni.AtomsToGob(decoded)
+	if err != nil {
+		t.Fatalf("AtomsToGob() on decoded atoms error = %v", err)
+	}
+	redecoded, err := omni.GobToAtoms(again)
+	if err != nil || len(redecoded) != 3 || redecoded[1] != nil {
+		t.Fatalf("second round trip failed: %v, %v", redecoded, err)
+	}
+}
+
+func TestJSONToAtom_NestedPropertyValuesStoredAsJSON(t *testing.T) {
+	jsonStr := `{"id":"a1","type":"item","properties":{"meta":{"tags":["x","y"],"n":1},"list":[1,"two",true],"big":1234567}}`
+
+	atom, err := omni.JSONToAtom(jsonStr)
+	if err != nil {
+		t.Fatalf("JSONToAtom() error = %v", err)
+	}
+
+	if got := atom.Get("meta"); got != `{"n":1,"tags":["x","y"]}` {
+		t.Fatalf("meta = %q, want JSON object text", got)
+	}
+	if got := atom.Get("list"); got != `[1,"two",true]` {
+		t.Fatalf("list = %q, want JSON array text", got)
+	}
+	if got := atom.Get("big"); got != "1234567" {
+		t.Fatalf("big = %q, want 1234567", got)
+	}
+
+	var meta map[string]any
+	if err := json.Unmarshal([]byte(atom.Get("meta")), &meta); err != nil {
+		t.Fatalf("stored meta is not valid JSON: %v", err)
+	}
+}
+
+func TestMapToAtoms_AcceptsNestedPropertyValues(t *testing.T) {
+	atoms := omni.MapToAtoms([]map[string]any{{
+		"id":         "a1",
+		"type":       "item",
+		"properties": map[string]any{"list": []any{"a", "b"}},
+	}})
+
+	if len(atoms) != 1 || atoms[0].Get("list") != `["a","b"]` {
+		t.Fatalf("MapToAtoms() did not keep nested property as JSON: %v", atoms)
+	}
+}
+
+// nestedAtomMap builds an atom map with the given number of nested levels.
+func nestedAtomMap(levels int) map[string]any {
+	root := map[string]any{"id": "n1", "type": "node"}
+	current := root
+	for i := 2; i <= levels; i++ {
+		child := map[string]any{"id": "n" + strconv.Itoa(i), "type": "node"}
+		current["children"] = []any{child}
+		current = child
+	}
+	return root
+}
+
+func TestMapToAtom_MaxDepth(t *testing.T) {
+	if _, err := omni.MapToAtom(nestedAtomMap(omni.DefaultMaxDepth)); err != nil {
+		t.Fatalf("MapToAtom() at the default max depth error = %v", err)
+	}
+
+	_, err := omni.MapToAtom(nestedAtomMap(omni.DefaultMaxDepth + 1))
+	if err == nil || !strings.Contains(err.Error(), "atom nesting exceeds max depth 1000") {
+		t.Fatalf("MapToAtom() error = %v, want max depth error", err)
+	}
+
+	if _, err := omni.MapToAtomWithMaxDepth(nestedAtomMap(3), 3); err != nil {
+		t.Fatalf("MapToAtomWithMaxDepth() within limit error = %v", err)
+	}
+	if _, err := omni.MapToAtomWithMaxDepth(nestedAtomMap(4), 3); err == nil {
+		t.Fatal("MapToAtomWithMaxDepth() expected error when exceeding limit")
+	}
+}
+
+func TestJSONToAtom_DeeplyNestedPayloadRejected(t *testing.T) {
+	data, err := json.Marshal(nestedAtomMap(omni.DefaultMaxDepth + 1))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	if _, err := omni.JSONToAtom(string(data)); err == nil || !strings.Contains(err.Error(), "exceeds max depth") {
+		t.Fatalf("JSONToAtom() error = %v, want max depth error", err)
+	}
+}
+
+func TestGobToAtom_MaxDepth(t *testing.T) {
+	atom, err := omni.MapToAtomWithMaxDepth(nestedAtomMap(5), 5)
+	if err != nil {
+		t.Fatalf("MapToAtomWithMaxDepth() error = %v", err)
+	}
+	data, err := atom.ToGob()
+	if err != nil {
+		t.Fatalf("ToGob() error = %v", err)
+	}
+
+	if _, err := omni.GobToAtomWithMaxDepth(data, 5); err != nil {
+		t.Fatalf("GobToAtomWithMaxDepth() within limit error = %v", err)
+	}
+
+	_, err = omni.GobToAtomWithMaxDepth(data, 4)
+	if err == nil || !strings.Contains(err.Error(), "atom nesting exceeds max depth 4") {
+		t.Fatalf("GobToAtomWithMaxDepth() error = %v, want max depth error", err)
+	}
+
+	deep, err := omni.MapToAtomWithMaxDepth(nestedAtomMap(omni.DefaultMaxDepth+1), omni.DefaultMaxDepth+1)
+	if err != nil {
+		t.Fatalf("MapToAtomWithMaxDepth() error = %v", err)
+	}
+	deepData, err := deep.ToGob()
+	if err != nil {
+		t.Fatalf("ToGob() error = %v", err)
+	}
+	if _, err := omni.GobToAtom(deepData); err == nil {
+		t.Fatal("GobToAtom() expected error for payload exceeding the default max depth")
+	}
+}
+
+func TestFromGob_MaxDepth(t *testing.T) {
+	deep, err := omni.MapToAtomWithMaxDepth(nestedAtomMap(omni.DefaultMaxDepth+1), omni.DefaultMaxDepth+1)
+	if err != nil {
+		t.Fatalf("MapToAtomWithMaxDepth() error = %v", err)
+	}
+	data, err := deep.ToGob()
+	if err != nil {
+		t.Fatalf("ToGob() error = %v", err)
+	}
+	checked, err := deep.(*omni.Atom).GobWithChecksum()
+	if err != nil {
+		t.Fatalf("GobWithChecksum() error = %v", err)
+	}
+
+	decoders := map[string]func() error{
+		"FromGob":            func() error { _, err := omni.FromGob(data); return err },
+		"FromGobShallow":     func() error { _, err := omni.FromGobShallow(data); return err },
+		"Atom.FromGob":       func() error { return (&omni.Atom{}).FromGob(data) },
+		"Atom.GobDecode":     func() error { return (&omni.Atom{}).GobDecode(data) },
+		"AtomFromGobChecked": func() error { _, err := omni.AtomFromGobChecked(checked); return err },
+	}
+	for name, decode := range decoders {
+		if err := decode(); err == nil || !strings.Contains(err.Error(), "atom nesting exceeds max depth 1000") {
+			t.Errorf("%s() error = %v, want max depth error", name, err)
+		}
+	}
+
+	within, err := omni.MapToAtom(nestedAtomMap(omni.DefaultMaxDepth))
+	if err != nil {
+		t.Fatalf("MapToAtom() error = %v", err)
+	}
+	data, err = within.ToGob()
+	if err != nil {
+		t.Fatalf("ToGob() error = %v", err)
+	}
+	if _, err := omni.FromGob(data); err != nil {
+		t.Fatalf("FromGob() at the default max depth error = %v", err)
+	}
+}
+
+func TestAtomsToJSONPretty(t *testing.T) {
+	parent := omni.NewAtom("page", omni.WithID("p1"), omni.WithProperty("title", "Home"))
+	parent.ChildAdd(omni.NewAtom("text", omni.WithID("t1")))
+	atoms := []omni.AtomInterface{parent, nil, omni.NewAtom("page", omni.WithID("p2"))}
+
+	pretty, err := omni.AtomsToJSONPretty(atoms)
+	if err != nil {
+		t.Fatalf("AtomsToJSONPretty() error = %v", err)
+	}
+	if !strings.Contains(pretty, "\n  {\n    \"children\"") {
+		t.Fatalf("expected indented output, got %s", pretty)
+	}
+
+	compact, err := omni.AtomsToJSON(atoms)
+	if err != nil {
+		t.Fatalf("AtomsToJSON() error = %v", err)
+	}
+	var prettyValue, compactValue any
+	if err := json.Unmarshal([]byte(pretty), &prettyValue); err != nil {
+		t.Fatalf("pretty output is not valid JSON: %v", err)
+	}
+	if err := json.Unmarshal([]byte(compact), &compactValue); err != nil {
+		t.Fatalf("compact output is not valid JSON: %v", err)
+	}
+	if !reflect.DeepEqual(prettyValue, compactValue) {
+		t.Fatal("expected pretty and compact output to be semantically equal")
+	}
+
+	if got, err := omni.AtomsToJSONPretty(nil); err != nil || got != "[]" {
+		t.Fatalf("AtomsToJSONPretty(nil) = %q, %v; want []", got, err)
+	}
+}
+
+func TestDecodeAtomsWithLimit(t *testing.T) {
+	atom := omni.NewAtom("item", omni.WithID("a1"), omni.WithProperty("payload", strings.Repeat("x", 100)))
+	atomData, err := atom.ToGob()
+	if err != nil {
+		t.Fatalf("ToGob() error = %v", err)
+	}
+	data, err := omni.AtomsToGob([]omni.AtomInterface{atom})
+	if err != nil {
+		t.Fatalf("AtomsToGob() error = %v", err)
+	}
+
+	decoded, err := omni.DecodeAtomsWithLimit(data, len(atomData))
+	if err != nil {
+		t.Fatalf("DecodeAtomsWithLimit() at the limit error = %v", err)
+	}
+	if len(decoded) != 1 || decoded[0].GetID() != "a1" {
+		t.Fatalf("unexpected decoded atoms: %v", decoded)
+	}
+
+	_, err = omni.DecodeAtomsWithLimit(data, len(atomData)-1)
+	if err == nil || !strings.Contains(err.Error(), "exceeds limit") {
+		t.Fatalf("DecodeAtomsWithLimit() beyond the limit error = %v, want limit error", err)
+	}
+
+	if _, err := omni.DecodeAtomsWithLimit(data, 0); err != nil {
+		t.Fatalf("DecodeAtomsWithLimit() with the default limit error = %v", err)
+	}
+}
+
+func TestMapToAtom_RoundTripsToMapChildren(t *testing.T) {
+	root := omni.NewAtom("page", omni.WithID("p1"))
+	root.ChildAdd(omni.NewAtom("text", omni.WithID("t1")))
+
+	m := root.ToMap()
+	if _, ok := m["children"].([]any); !ok {
+		t.Fatalf("ToMap children type = %T, want []any", m["children"])
+	}
+
+	atom, err := omni.MapToAtom(m)
+	if err != nil {
+		t.Fatalf("MapToAtom() error = %v", err)
+	}
+	children := atom.ChildrenGet()
+	if len(children) != 1 || children[0].GetID() != "t1" || children[0].GetType() != "text" {
+		t.Fatalf("children not round-tripped: %v", children)
+	}
+}
+
+func TestDecodeAtomsPartial_TruncatedData(t *testing.T) {
+	atoms := []omni.AtomInterface{
+		omni.NewAtom("item", omni.WithID("a1")),
+		nil,
+		omni.NewAtom("item", omni.WithID("a2")),
+		omni.NewAtom("item", omni.WithID("a3"), omni.WithProperty("k", "v")),
+	}
+	data, err := omni.AtomsToGob(atoms)
+	if err != nil {
+		t.Fatalf("AtomsToGob() error = %v", err)
+	}
+
+	complete, err := omni.DecodeAtomsPartial(data)
+	if err != nil || len(complete) != 4 {
+		t.Fatalf("DecodeAtomsPartial() on complete data = %d atoms, %v", len(complete), err)
+	}
+
+	// Cut the last atom in half
+	truncated := data[:len(data)-10]
+
+	if _, err := omni.GobToAtoms(truncated); err == nil {
+		t.Fatal("expected GobToAtoms to reject truncated data")
+	}
+
+	salvaged, err := omni.DecodeAtomsPartial(truncated)
+	if err == nil || !strings.Contains(err.Error(), "atom 3") {
+		t.Fatalf("DecodeAtomsPartial() error = %v, want an error about atom 3", err)
+	}
+	if len(salvaged) != 3 || salvaged[0].GetID() != "a1" || salvaged[1] != nil || salvaged[2].GetID() != "a2" {
+		t.Fatalf("DecodeAtomsPartial() salvaged %v", salvaged)
+	}
+}
@@ -0,0 +1,62 @@
+package omni
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+)
+
+// checksumSize is the number of bytes used by the CRC32 checksum
+// appended to gob data by GobWithChecksum.
+const checksumSize = 4
+
+// ErrChecksumMismatch is returned by AtomFromGobChecked when the
+// stored checksum does not match the checksum of the gob data.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// GobWithChecksum encodes the atom to gob and appends a CRC32 (IEEE)
+// checksum of the encoded bytes in big-endian order.
+//
+// The result can be decoded with AtomFromGobChecked, which verifies
+// the checksum before decoding to detect corrupted data.
+func (a *Atom) GobWithChecksum() ([]byte, error) {
+	data, err := a.ToGob()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]byte, len(data)+checksumSize)
+	copy(result, data)
+	binary.BigEndian.PutUint32(result[len(data):], crc32.ChecksumIEEE(data))
+
+	return result, nil
+}
+
+// AtomFromGobChecked decodes an atom from data produced by GobWithChecksum.
+//
+// Business logic:
+// - Returns an error if the data is too short to contain a checksum
+// - Verifies the trailing CRC32 checksum against the gob payload
+// - Returns ErrChecksumMismatch if the checksum does not match
+// - Decodes the payload using FromGob
+//
+// Parameters:
+//   - data: gob-encoded atom followed by a 4-byte CRC32 checksum
+//
+// Returns:
+//   - *Atom: the decoded atom
+//   - error: if the checksum does not match or decoding fails
+func AtomFromGobChecked(data []byte) (*Atom, error) {
+	if len(data) <= checksumSize {
+		return nil, errors.New("data too short to contain checksum")
+	}
+
+	payload := data[:len(data)-checksumSize]
+	expected := binary.BigEndian.Uint32(data[len(data)-checksumSize:])
+
+	if crc32.ChecksumIEEE(payload) != expected {
+		return nil, ErrChecksumMismatch
+	}
+
+	return FromGob(payload)
+}
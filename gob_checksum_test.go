@@ -0,0 +1,52 @@
+package omni
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGobWithChecksum_RoundTrip(t *testing.T) {
+	root := NewAtom("root", WithID("root"))
+	root.Set("title", "hello")
+	root.ChildAdd(NewAtom("child", WithID("c1")))
+
+	data, err := root.(*Atom).GobWithChecksum()
+	if err != nil {
+		t.Fatalf("GobWithChecksum() error = %v", err)
+	}
+
+	got, err := AtomFromGobChecked(data)
+	if err != nil {
+		t.Fatalf("AtomFromGobChecked() error = %v", err)
+	}
+	if got.GetID() != "root" || got.Get("title") != "hello" {
+		t.Fatalf("unexpected decoded atom: id=%q title=%q", got.GetID(), got.Get("title"))
+	}
+	if got.ChildrenLength() != 1 || got.ChildrenGet()[0].GetID() != "c1" {
+		t.Fatalf("expected child c1 to be decoded")
+	}
+}
+
+func TestAtomFromGobChecked_DetectsCorruption(t *testing.T) {
+	root := NewAtom("root", WithID("root"))
+	root.Set("title", "hello")
+
+	data, err := root.(*Atom).GobWithChecksum()
+	if err != nil {
+		t.Fatalf("GobWithChecksum() error = %v", err)
+	}
+
+	// Flip a bit in the payload
+	data[len(data)/2] ^= 0x01
+
+	_, err = AtomFromGobChecked(data)
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+}
+
+func TestAtomFromGobChecked_TooShort(t *testing.T) {
+	if _, err := AtomFromGobChecked([]byte{1, 2}); err == nil {
+		t.Fatal("expected error for short data, got nil")
+	}
+}
@@ -0,0 +1,43 @@
+package omni
+
+import (
+	"sync"
+
+	"github.com/dracory/uid"
+)
+
+// idGenerator generates the IDs of atoms created without one, guarded by idGeneratorMu.
+var (
+	idGeneratorMu sync.RWMutex
+	idGenerator   = defaultIDGenerator
+)
+
+// defaultIDGenerator generates a human-readable UID.
+func defaultIDGenerator() string {
+	return uid.HumanUid()
+}
+
+// SetIDGenerator replaces the function NewAtom uses to generate an ID when
+// none is provided, for example with a deterministic counter in tests or a
+// custom scheme such as ULIDs. Passing nil restores the default, uid.HumanUid.
+//
+// The generator is global: SetIDGenerator is safe to call concurrently with
+// NewAtom, but the generator itself may be called from several goroutines at
+// once and must be safe for concurrent use. Tests replacing it should not run
+// in parallel with tests relying on the default.
+func SetIDGenerator(gen func() string) {
+	if gen == nil {
+		gen = defaultIDGenerator
+	}
+	idGeneratorMu.Lock()
+	defer idGeneratorMu.Unlock()
+	idGenerator = gen
+}
+
+// generateID returns a new ID from the current ID generator.
+func generateID() string {
+	idGeneratorMu.RLock()
+	gen := idGenerator
+	idGeneratorMu.RUnlock()
+	return gen()
+}
@@ -0,0 +1,31 @@
+package omni
+
+import (
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSetIDGenerator(t *testing.T) {
+	var counter atomic.Int64
+	SetIDGenerator(func() string {
+		return "id-" + strconv.FormatInt(counter.Add(1), 10)
+	})
+	t.Cleanup(func() { SetIDGenerator(nil) })
+
+	first := NewAtom("item")
+	second := NewAtom("item")
+	explicit := NewAtom("item", WithID("explicit"))
+
+	if first.GetID() != "id-1" || second.GetID() != "id-2" {
+		t.Fatalf("expected deterministic IDs, got %q and %q", first.GetID(), second.GetID())
+	}
+	if explicit.GetID() != "explicit" || counter.Load() != 2 {
+		t.Fatal("expected the generator not to be called when an ID is provided")
+	}
+
+	SetIDGenerator(nil)
+	if id := NewAtom("item").GetID(); id == "" || id == "id-3" {
+		t.Fatalf("expected SetIDGenerator(nil) to restore the default, got %q", id)
+	}
+}
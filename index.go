@@ -0,0 +1,68 @@
+package omni
+
+import "sync"
+
+// Index is a lookup table from atom ID to atom for a tree, built with BuildIndex.
+// It turns repeated FindAtomByID lookups (O(n) each) into O(1) lookups after a
+// single O(n) build.
+//
+// The index is a snapshot of the tree at build time: structural changes
+// (adding, removing or re-parenting atoms, or changing IDs) invalidate it.
+// Call Rebuild after such changes.
+//
+// If several atoms share the same ID, the first one found in pre-order
+// is indexed, matching the behavior of FindAtomByID.
+type Index struct {
+	root AtomInterface
+	byID map[string]AtomInterface
+	mu   sync.RWMutex
+}
+
+// BuildIndex walks the tree rooted at root once and returns an Index of
+// all atoms by ID. A nil root results in an empty index.
+func BuildIndex(root AtomInterface) *Index {
+	index := &Index{root: root}
+	index.Rebuild()
+	return index
+}
+
+// Get returns the atom with the given ID, or nil if it is not indexed.
+func (i *Index) Get(id string) AtomInterface {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.byID[id]
+}
+
+// Len returns the number of indexed IDs.
+func (i *Index) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return len(i.byID)
+}
+
+// Rebuild walks the tree again and replaces the indexed entries.
+// It should be called after structural changes to the tree.
+func (i *Index) Rebuild() {
+	byID := make(map[string]AtomInterface)
+	indexAtoms(i.root, byID)
+
+	i.mu.Lock()
+	i.byID = byID
+	i.mu.Unlock()
+}
+
+// indexAtoms adds the atom and all its descendants to byID in pre-order,
+// keeping the first atom seen for each ID.
+func indexAtoms(atom AtomInterface, byID map[string]AtomInterface) {
+	if atom == nil {
+		return
+	}
+
+	if _, exists := byID[atom.GetID()]; !exists {
+		byID[atom.GetID()] = atom
+	}
+
+	for _, child := range atom.ChildrenGet() {
+		indexAtoms(child, byID)
+	}
+}
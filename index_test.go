@@ -0,0 +1,57 @@
+package omni
+
+import "testing"
+
+func TestBuildIndex_Get(t *testing.T) {
+	root := NewAtom("root", WithID("root"))
+	a := NewAtom("node", WithID("a"))
+	a.ChildAdd(NewAtom("leaf", WithID("a1")))
+	root.ChildAdd(a)
+	root.ChildAdd(NewAtom("node", WithID("b")))
+
+	index := BuildIndex(root)
+	if index.Len() != 4 {
+		t.Fatalf("expected 4 indexed atoms, got %d", index.Len())
+	}
+	for _, id := range []string{"root", "a", "a1", "b"} {
+		if got := index.Get(id); got == nil || got.GetID() != id {
+			t.Fatalf("expected to find %q, got %v", id, got)
+		}
+	}
+	if got := index.Get("missing"); got != nil {
+		t.Fatalf("expected nil for missing id, got %v", got)
+	}
+}
+
+func TestBuildIndex_DuplicateIDsKeepFirst(t *testing.T) {
+	root := NewAtom("root", WithID("root"))
+	root.ChildAdd(NewAtom("first", WithID("dup")))
+	root.ChildAdd(NewAtom("second", WithID("dup")))
+
+	got := BuildIndex(root).Get("dup")
+	if got == nil || got.GetType() != "first" {
+		t.Fatalf("expected first atom with duplicate id, got %v", got)
+	}
+}
+
+func TestIndex_Rebuild(t *testing.T) {
+	root := NewAtom("root", WithID("root"))
+	index := BuildIndex(root)
+
+	root.ChildAdd(NewAtom("node", WithID("late")))
+	if index.Get("late") != nil {
+		t.Fatal("expected stale index not to contain atom added after build")
+	}
+
+	index.Rebuild()
+	if index.Get("late") == nil {
+		t.Fatal("expected rebuilt index to contain new atom")
+	}
+}
+
+func TestBuildIndex_NilRoot(t *testing.T) {
+	index := BuildIndex(nil)
+	if index.Len() != 0 || index.Get("any") != nil {
+		t.Fatal("expected empty index for nil root")
+	}
+}
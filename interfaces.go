@@ -1,5 +1,7 @@
 package omni
 
+import "time"
+
 // AtomInterface is the universal interface that all composable primitives must satisfy.
 // It defines the methods necessary for the system to understand and process any atom,
 // regardless of its specific type.
@@ -60,3 +62,21 @@ type AtomInterface interface {
 	// This is useful for memory profiling and monitoring.
 	MemoryUsage() int
 }
+
+// PropertyInterface is a named string value, used to exchange
+// atom properties with code that works with the property abstraction.
+type PropertyInterface interface {
+	GetName() string
+	SetName(name string) PropertyInterface
+
+	GetValue() string
+	SetValue(value string) PropertyInterface
+
+	// Typed access, stored as the canonical string form of the value
+	GetValueInt() (int, error)
+	SetValueInt(value int) PropertyInterface
+	GetValueBool() (bool, error)
+	SetValueBool(value bool) PropertyInterface
+	GetValueTime() (time.Time, error)
+	SetValueTime(value time.Time) PropertyInterface
+}
@@ -90,8 +90,8 @@ func TestAtomToMap(t *testing.T) {
 		t.Error("Map values do not match expected")
 	}
 
-	children, ok := m["children"].([]map[string]interface{})
-	if !ok || len(children) != 1 || children[0]["id"] != "child-1" {
+	children, ok := m["children"].([]any)
+	if !ok || len(children) != 1 || children[0].(map[string]any)["id"] != "child-1" {
 		t.Errorf("Children in map do not match expected. Got: %+v", children)
 	}
 
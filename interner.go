@@ -0,0 +1,48 @@
+package omni
+
+import "sync"
+
+// Interner deduplicates identical strings, so atoms sharing an Interner
+// (see WithInterner) store repeated property keys and values once, sharing
+// the same backing bytes. It is safe for concurrent use.
+//
+// Interned strings are kept for the lifetime of the Interner, so use it for
+// trees with a limited set of repeated keys and values, and drop it together
+// with the trees using it.
+type Interner struct {
+	mu      sync.Mutex
+	strings map[string]string
+}
+
+// NewInterner returns an empty Interner.
+func NewInterner() *Interner {
+	return &Interner{strings: make(map[string]string)}
+}
+
+// Intern returns the canonical instance of s: the first string equal to s
+// that was interned.
+func (in *Interner) Intern(s string) string {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if canonical, ok := in.strings[s]; ok {
+		return canonical
+	}
+	in.strings[s] = s
+	return s
+}
+
+// Len returns the number of distinct strings interned.
+func (in *Interner) Len() int {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return len(in.strings)
+}
+
+// internLocked returns the canonical instance of s if the atom has an
+// interner, or s itself otherwise. The caller must hold a.mu.
+func (a *Atom) internLocked(s string) string {
+	if a.interner == nil {
+		return s
+	}
+	return a.interner.Intern(s)
+}
@@ -0,0 +1,116 @@
+package omni
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+func TestInterner_Intern(t *testing.T) {
+	in := NewInterner()
+	first := in.Intern(fmt.Sprint("status-", 1))
+	second := in.Intern(fmt.Sprint("status-", 1))
+
+	if first != second || unsafe.StringData(first) != unsafe.StringData(second) {
+		t.Fatal("expected equal strings to share backing storage")
+	}
+	if in.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", in.Len())
+	}
+}
+
+func TestAtom_WithInterner(t *testing.T) {
+	in := NewInterner()
+	a := NewAtom("item", WithInterner(in), WithProperties(map[string]string{fmt.Sprint("sta", "tus"): fmt.Sprint("act", "ive")})).(*Atom)
+	b := NewAtom("item", WithInterner(in)).(*Atom)
+	b.Set(fmt.Sprint("sta", "tus"), fmt.Sprint("act", "ive"))
+
+	if a.Get("status") != "active" || b.Get("status") != "active" {
+		t.Fatal("expected interning not to change values")
+	}
+	if unsafe.StringData(a.Get("status")) != unsafe.StringData(b.Get("status")) {
+		t.Fatal("expected identical values to share backing storage")
+	}
+
+	b.SetAll(map[string]string{fmt.Sprint("sta", "tus"): fmt.Sprint("act", "ive")})
+	if unsafe.StringData(b.Get("status")) != unsafe.StringData(a.Get("status")) {
+		t.Fatal("expected SetAll to intern values")
+	}
+}
+
+func TestAtom_WithInterner_AllWritePaths(t *testing.T) {
+	in := NewInterner()
+	shared := in.Intern("active")
+	same := func(value string) bool { return unsafe.StringData(value) == unsafe.StringData(shared) }
+
+	a := NewAtom("item", WithInterner(in), WithData(map[string]string{"data": fmt.Sprint("act", "ive")})).(*Atom)
+	a.SetIfAbsent("absent", fmt.Sprint("act", "ive"))
+	if !same(a.Get("data")) || !same(a.Get("absent")) {
+		t.Fatal("expected WithData and SetIfAbsent to intern values")
+	}
+
+	a.PropertiesSet([]PropertyInterface{NewProperty("prop", fmt.Sprint("act", "ive"))})
+	if !same(a.Get("prop")) {
+		t.Fatal("expected PropertiesSet to intern values")
+	}
+}
+
+func TestAtom_WithInterner_Concurrent(t *testing.T) {
+	in := NewInterner()
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			a := NewAtom("item", WithInterner(in))
+			for i := 0; i < 100; i++ {
+				a.Set(fmt.Sprint("key-", i%5), fmt.Sprint("value-", i%3))
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if in.Len() != 8 {
+		t.Fatalf("Len() = %d, want 8 distinct strings", in.Len())
+	}
+}
+
+// buildRepetitiveTree builds a tree of atoms whose property keys and values
+// repeat, created at runtime as they would be when decoded from input.
+func buildRepetitiveTree(in *Interner) AtomInterface {
+	root := NewAtom("root", WithID("root"), WithInterner(in))
+	for i := 0; i < 1000; i++ {
+		child := NewAtom("row", WithID(fmt.Sprint("r", i)), WithInterner(in))
+		child.Set(fmt.Sprint("status"), fmt.Sprint("waiting for review by the editorial team, stage ", i%3))
+		child.Set(fmt.Sprint("category"), fmt.Sprint("https://example.com/catalog/categories/", i%5))
+		root.ChildAdd(child)
+	}
+	return root
+}
+
+// benchmarkRetainedTree reports the heap retained by each repetitive tree.
+func benchmarkRetainedTree(b *testing.B, newInterner func() *Interner) {
+	var before, after runtime.MemStats
+	trees := make([]AtomInterface, 0, b.N)
+
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	for i := 0; i < b.N; i++ {
+		trees = append(trees, buildRepetitiveTree(newInterner()))
+	}
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+
+	b.ReportMetric((float64(after.HeapAlloc)-float64(before.HeapAlloc))/float64(b.N), "retained-B/tree")
+	runtime.KeepAlive(trees)
+}
+
+func BenchmarkRepetitiveTree_NoInterner(b *testing.B) {
+	benchmarkRetainedTree(b, func() *Interner { return nil })
+}
+
+func BenchmarkRepetitiveTree_WithInterner(b *testing.B) {
+	benchmarkRetainedTree(b, NewInterner)
+}
@@ -0,0 +1,52 @@
+package omni
+
+import "iter"
+
+// All returns an iterator over the atom and all its descendants, in pre-order:
+//
+//	for atom := range tree.All() {
+//		...
+//	}
+//
+// No lock is held while the loop body runs, so the body may read and mutate
+// the tree. Each atom's children are copied when the atom is reached, so
+// concurrent mutations never race with the iteration; changes made to a part
+// of the tree that was not visited yet are seen by the iteration.
+func (a *Atom) All() iter.Seq[AtomInterface] {
+	return func(yield func(AtomInterface) bool) {
+		allAtoms(a, yield)
+	}
+}
+
+// Children returns an iterator over the atom's immediate children.
+// The children are copied when the iteration starts (see ChildrenGet),
+// so the loop body may mutate the atom's children safely.
+func (a *Atom) Children() iter.Seq[AtomInterface] {
+	return func(yield func(AtomInterface) bool) {
+		for _, child := range a.ChildrenGet() {
+			if child == nil {
+				continue
+			}
+			if !yield(child) {
+				return
+			}
+		}
+	}
+}
+
+// allAtoms yields atom and its descendants in pre-order,
+// returning false once yield asks to stop.
+func allAtoms(atom AtomInterface, yield func(AtomInterface) bool) bool {
+	if !yield(atom) {
+		return false
+	}
+	for _, child := range atom.ChildrenGet() {
+		if child == nil {
+			continue
+		}
+		if !allAtoms(child, yield) {
+			return false
+		}
+	}
+	return true
+}
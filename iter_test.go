@@ -0,0 +1,70 @@
+package omni
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestAtom_All_PreOrder(t *testing.T) {
+	var ids []string
+	for atom := range newTestTree().All() {
+		ids = append(ids, atom.GetID())
+	}
+
+	if got := strings.Join(ids, ","); got != "p1,s1,t1,t2" {
+		t.Fatalf("All() visited %s, want p1,s1,t1,t2", got)
+	}
+}
+
+func TestAtom_All_Break(t *testing.T) {
+	var ids []string
+	for atom := range newTestTree().All() {
+		ids = append(ids, atom.GetID())
+		if atom.GetID() == "t1" {
+			break
+		}
+	}
+
+	if got := strings.Join(ids, ","); got != "p1,s1,t1" {
+		t.Fatalf("All() visited %s, want p1,s1,t1", got)
+	}
+}
+
+func TestAtom_Children(t *testing.T) {
+	root := newTestTree()
+
+	var ids []string
+	for child := range root.Children() {
+		ids = append(ids, child.GetID())
+		root.ChildDeleteByID(child.GetID())
+	}
+
+	if got := strings.Join(ids, ","); got != "s1,t2" {
+		t.Fatalf("Children() visited %s, want s1,t2", got)
+	}
+	if root.ChildrenLength() != 0 {
+		t.Fatal("expected the children deleted in the loop to be removed")
+	}
+}
+
+func TestAtom_All_ConcurrentMutation(t *testing.T) {
+	root := newTestTree()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			root.ChildAdd(NewAtom("text"))
+			root.Set("count", "x")
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		for atom := range root.All() {
+			_ = atom.GetAll()
+		}
+	}
+	wg.Wait()
+}
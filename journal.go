@@ -0,0 +1,283 @@
+package omni
+
+import "sync"
+
+// Journal wraps an atom and records the mutations made through it,
+// so they can be reverted with Undo and re-applied with Redo.
+//
+// Journal implements AtomInterface: the mutators (SetID, SetType, Set, Remove,
+// SetAll, ChildAdd, ChildDeleteByID, ChildrenAdd and ChildrenSet) are recorded,
+// and all other methods are passed through to the wrapped atom. Only
+// mutations made through the Journal itself are recorded; mutations made
+// directly on the wrapped atom or on its children are not, and may make
+// later undos inaccurate.
+type Journal struct {
+	AtomInterface
+
+	mu    sync.Mutex
+	undo  []journalEntry
+	redo  []journalEntry
+	limit int
+}
+
+// journalEntry holds the functions reverting and re-applying one mutation.
+type journalEntry struct {
+	undo func()
+	redo func()
+}
+
+// Compile-time check that *Journal satisfies AtomInterface.
+var _ AtomInterface = (*Journal)(nil)
+
+// NewJournal returns a Journal recording mutations of atom, keeping at most
+// limit undoable operations (the oldest are dropped). A limit of zero or
+// less keeps every operation.
+func NewJournal(atom AtomInterface, limit int) *Journal {
+	return &Journal{AtomInterface: atom, limit: limit}
+}
+
+// Atom returns the wrapped atom.
+func (j *Journal) Atom() AtomInterface {
+	return j.AtomInterface
+}
+
+// Undo reverts the most recent recorded operation and reports whether
+// there was one to revert.
+func (j *Journal) Undo() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if len(j.undo) == 0 {
+		return false
+	}
+	entry := j.undo[len(j.undo)-1]
+	j.undo = j.undo[:len(j.undo)-1]
+	entry.undo()
+	j.redo = append(j.redo, entry)
+	return true
+}
+
+// Redo re-applies the most recently undone operation and reports whether
+// there was one to re-apply. Recording a new operation clears the redo history.
+func (j *Journal) Redo() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if len(j.redo) == 0 {
+		return false
+	}
+	entry := j.redo[len(j.redo)-1]
+	j.redo = j.redo[:len(j.redo)-1]
+	entry.redo()
+	j.undo = append(j.undo, entry)
+	return true
+}
+
+// UndoLen returns the number of operations that can be undone.
+func (j *Journal) UndoLen() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return len(j.undo)
+}
+
+// RedoLen returns the number of operations that can be redone.
+func (j *Journal) RedoLen() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return len(j.redo)
+}
+
+// record applies a mutation and records how to revert it.
+// The caller must hold j.mu.
+func (j *Journal) record(undo, redo func()) {
+	redo()
+	j.push(undo, redo)
+}
+
+// push records an already applied mutation. The caller must hold j.mu.
+func (j *Journal) push(undo, redo func()) {
+	j.undo = append(j.undo, journalEntry{undo: undo, redo: redo})
+	if j.limit > 0 && len(j.undo) > j.limit {
+		j.undo = j.undo[len(j.undo)-j.limit:]
+	}
+	j.redo = nil
+}
+
+// SetID sets the atom's ID.
+func (j *Journal) SetID(id string) AtomInterface {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	atom := j.AtomInterface
+	old := atom.GetID()
+	j.record(func() { atom.SetID(old) }, func() { atom.SetID(id) })
+	return j
+}
+
+// SetType sets the atom's type.
+func (j *Journal) SetType(atomType string) AtomInterface {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	atom := j.AtomInterface
+	old := atom.GetType()
+	j.record(func() { atom.SetType(old) }, func() { atom.SetType(atomType) })
+	return j
+}
+
+// Set sets the value for the given key. A write the atom rejects (see
+// SetChecked), or one that leaves its properties unchanged, is not recorded.
+// The key is recorded as stored, which matters when the atom normalizes keys.
+func (j *Journal) Set(key, value string) AtomInterface {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	atom := j.AtomInterface
+	before := atom.GetAll()
+	if target, ok := AsAtom(atom); ok {
+		if err := target.SetChecked(key, value); err != nil {
+			return j
+		}
+	} else {
+		atom.Set(key, value)
+	}
+	stored, newValue, changed := changedProperty(before, atom.GetAll())
+	if !changed {
+		return j
+	}
+	old, existed := before[stored]
+	j.push(func() {
+		if existed {
+			atom.Set(stored, old)
+		} else {
+			atom.Remove(stored)
+		}
+	}, func() { atom.Set(stored, newValue) })
+	return j
+}
+
+// Remove removes the value for the given key.
+// Removing a missing key is not recorded.
+func (j *Journal) Remove(key string) AtomInterface {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	atom := j.AtomInterface
+	if !atom.Has(key) {
+		return j
+	}
+	old := atom.Get(key)
+	j.record(func() { atom.Set(key, old) }, func() { atom.Remove(key) })
+	return j
+}
+
+// SetAll replaces all properties of the atom.
+func (j *Journal) SetAll(properties map[string]string) AtomInterface {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	atom := j.AtomInterface
+	old, oldID, oldType := atom.GetAll(), atom.GetID(), atom.GetType()
+	undo := func() {
+		// SetAll may also have set the id and type
+		atom.SetAll(old)
+		atom.SetID(oldID)
+		atom.SetType(oldType)
+	}
+	j.record(undo, func() { atom.SetAll(properties) })
+	return j
+}
+
+// ChildAdd adds a child atom. Adding a nil child, or a child the atom
+// skips (see WithUniqueChildIDs), is not recorded.
+func (j *Journal) ChildAdd(child AtomInterface) AtomInterface {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	atom := j.AtomInterface
+	if child == nil {
+		return j
+	}
+	index := atom.ChildrenLength()
+	atom.ChildAdd(child)
+	if atom.ChildrenLength() == index {
+		return j
+	}
+	j.push(
+		func() { removeChildAt(atom, index) },
+		func() { insertChildAt(atom, index, child) },
+	)
+	return j
+}
+
+// ChildDeleteByID removes the first immediate child with the given ID.
+// Deleting a missing child is not recorded.
+func (j *Journal) ChildDeleteByID(id string) AtomInterface {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	atom := j.AtomInterface
+	for index, child := range atom.ChildrenGet() {
+		if child != nil && child.GetID() == id {
+			j.record(
+				func() { insertChildAt(atom, index, child) },
+				func() { removeChildAt(atom, index) },
+			)
+			break
+		}
+	}
+	return j
+}
+
+// ChildrenAdd adds multiple child atoms. Adding no children, or only
+// children the atom skips, is not recorded.
+func (j *Journal) ChildrenAdd(children []AtomInterface) AtomInterface {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	atom := j.AtomInterface
+	old := atom.ChildrenGet()
+	atom.ChildrenAdd(children)
+	added := atom.ChildrenGet()
+	if len(added) == len(old) {
+		return j
+	}
+	j.push(func() { atom.ChildrenSet(old) }, func() { atom.ChildrenSet(added) })
+	return j
+}
+
+// ChildrenSet replaces all children of the atom.
+func (j *Journal) ChildrenSet(children []AtomInterface) AtomInterface {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	atom := j.AtomInterface
+	old := atom.ChildrenGet()
+	j.record(func() { atom.ChildrenSet(old) }, func() { atom.ChildrenSet(children) })
+	return j
+}
+
+// changedProperty returns the key whose value differs between before and
+// after, as left by a single Set, and its new value.
+func changedProperty(before, after map[string]string) (string, string, bool) {
+	for key, value := range after {
+		if old, ok := before[key]; !ok || old != value {
+			return key, value, true
+		}
+	}
+	return "", "", false
+}
+
+// insertChildAt inserts child at the given index of atom's children.
+func insertChildAt(atom AtomInterface, index int, child AtomInterface) {
+	children := atom.ChildrenGet()
+	if index > len(children) {
+		index = len(children)
+	}
+	result := make([]AtomInterface, 0, len(children)+1)
+	result = append(result, children[:index]...)
+	result = append(result, child)
+	result = append(result, children[index:]...)
+	atom.ChildrenSet(result)
+}
+
+// removeChildAt removes the child at the given index of atom's children.
+func removeChildAt(atom AtomInterface, index int) {
+	children := atom.ChildrenGet()
+	if index >= len(children) {
+		return
+	}
+	result := make([]AtomInterface, 0, len(children)-1)
+	result = append(result, children[:index]...)
+	result = append(result, children[index+1:]...)
+	atom.ChildrenSet(result)
+}
@@ -0,0 +1,169 @@
+package omni
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestJournal_UndoRedoProperties(t *testing.T) {
+	atom := NewAtom("doc", WithID("d1"), WithProperty("title", "v1"))
+	j := NewJournal(atom, 0)
+
+	j.Set("title", "v2").Set("lang", "en").Remove("title").SetType("document")
+
+	if atom.Has("title") || atom.Get("lang") != "en" || atom.GetType() != "document" {
+		t.Fatalf("expected mutations to be applied, got %v", atom)
+	}
+
+	// Remove title, then restore it to v2
+	j.Undo()
+	j.Undo()
+	if atom.GetType() != "doc" || atom.Get("title") != "v2" {
+		t.Fatalf("unexpected state after two undos: %v", atom.GetAll())
+	}
+
+	// Undo lang (absent before) and the first title change
+	j.Undo()
+	j.Undo()
+	if atom.Has("lang") || atom.Get("title") != "v1" {
+		t.Fatalf("unexpected state after undoing everything: %v", atom.GetAll())
+	}
+	if j.Undo() {
+		t.Fatal("expected Undo to report false with nothing left")
+	}
+
+	for j.Redo() {
+	}
+	if atom.Has("title") || atom.Get("lang") != "en" || atom.GetType() != "document" {
+		t.Fatalf("expected Redo to re-apply everything, got %v", atom)
+	}
+}
+
+func TestJournal_UndoSetAllRestoresIDAndType(t *testing.T) {
+	atom := NewAtom("doc", WithID("d1"), WithProperty("title", "v1"))
+	j := NewJournal(atom, 0)
+
+	j.SetAll(map[string]string{"id": "d2", "type": "page", "lang": "en"})
+	if atom.GetID() != "d2" || atom.GetType() != "page" {
+		t.Fatalf("expected SetAll to set the id and type, got %v", atom)
+	}
+
+	j.Undo()
+	if atom.GetID() != "d1" || atom.GetType() != "doc" || atom.Get("title") != "v1" || atom.Has("lang") {
+		t.Fatalf("unexpected state after undo: %v %v", atom, atom.GetAll())
+	}
+}
+
+func TestJournal_UndoRedoChildren(t *testing.T) {
+	atom := NewAtom("doc")
+	atom.ChildAdd(NewAtom("item", WithID("a")))
+	atom.ChildAdd(NewAtom("item", WithID("b")))
+	j := NewJournal(atom, 0)
+
+	j.ChildDeleteByID("a")
+	j.ChildAdd(NewAtom("item", WithID("c")))
+	j.ChildrenAdd([]AtomInterface{NewAtom("item", WithID("d"))})
+
+	assertChildIDs(t, atom, "b", "c", "d")
+
+	j.Undo()
+	assertChildIDs(t, atom, "b", "c")
+	j.Undo()
+	assertChildIDs(t, atom, "b")
+	j.Undo()
+	assertChildIDs(t, atom, "a", "b")
+
+	j.Redo()
+	assertChildIDs(t, atom, "b")
+
+	j.ChildrenSet(nil)
+	assertChildIDs(t, atom)
+	if j.RedoLen() != 0 {
+		t.Fatal("expected a new operation to clear the redo history")
+	}
+	j.Undo()
+	assertChildIDs(t, atom, "b")
+}
+
+func TestJournal_NoOpsAreNotRecorded(t *testing.T) {
+	j := NewJournal(NewAtom("doc", WithUniqueChildIDs()), 0)
+	j.ChildAdd(NewAtom("item", WithID("a")))
+
+	j.Remove("missing")
+	j.ChildDeleteByID("missing")
+	j.ChildAdd(nil)
+	j.ChildAdd(NewAtom("item", WithID("a")))
+
+	if j.UndoLen() != 1 {
+		t.Fatalf("expected only the first ChildAdd to be recorded, got %d", j.UndoLen())
+	}
+}
+
+func TestJournal_Limit(t *testing.T) {
+	atom := NewAtom("doc")
+	j := NewJournal(atom, 2)
+
+	j.Set("n", "1").Set("n", "2").Set("n", "3")
+
+	if j.UndoLen() != 2 {
+		t.Fatalf("UndoLen() = %d, want 2", j.UndoLen())
+	}
+	j.Undo()
+	j.Undo()
+	if atom.Get("n") != "1" || j.Undo() {
+		t.Fatalf("expected the oldest operation to be dropped, got n=%q", atom.Get("n"))
+	}
+}
+
+func assertChildIDs(t *testing.T, atom AtomInterface, want ...string) {
+	t.Helper()
+	children := atom.ChildrenGet()
+	if len(children) != len(want) {
+		t.Fatalf("children = %v, want IDs %v", children, want)
+	}
+	for i, child := range children {
+		if child.GetID() != want[i] {
+			t.Fatalf("children = %v, want IDs %v", children, want)
+		}
+	}
+}
+
+func TestJournal_SetRecordsNormalizedKeys(t *testing.T) {
+	lower := func(key string) (string, error) {
+		if key == "" {
+			return "", errors.New("empty key")
+		}
+		return strings.ToLower(key), nil
+	}
+	atom := NewAtom("doc", WithKeyValidator(lower))
+	j := NewJournal(atom, 0)
+
+	j.Set("", "rejected")
+	j.Set("Title", "v1")
+	if j.UndoLen() != 1 {
+		t.Fatalf("UndoLen() = %d, want 1 (rejected writes are not recorded)", j.UndoLen())
+	}
+	j.Set("TITLE", "v1")
+	if j.UndoLen() != 1 {
+		t.Fatalf("UndoLen() = %d, want 1 (unchanged writes are not recorded)", j.UndoLen())
+	}
+
+	j.Undo()
+	if atom.Has("title") {
+		t.Fatalf("expected Undo to remove the normalized key, got %v", atom.GetAll())
+	}
+	j.Redo()
+	if atom.Get("title") != "v1" {
+		t.Fatalf("expected Redo to restore the normalized key, got %v", atom.GetAll())
+	}
+}
+
+func TestJournal_ChildrenAddNothingIsNotRecorded(t *testing.T) {
+	j := NewJournal(NewAtom("doc"), 0)
+	j.ChildrenAdd(nil)
+	j.ChildrenAdd([]AtomInterface{nil})
+	if j.UndoLen() != 0 {
+		t.Fatalf("UndoLen() = %d, want 0", j.UndoLen())
+	}
+}
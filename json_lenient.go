@@ -0,0 +1,87 @@
+package omni
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// AtomParseError is returned by ParseAtomsLenient for an array
+// element that could not be converted to an atom.
+type AtomParseError struct {
+	// Index is the position of the element in the JSON array.
+	Index int
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *AtomParseError) Error() string {
+	return fmt.Sprintf("atom %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *AtomParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseAtomsLenient converts a JSON array of atoms to a slice of AtomInterface
+// like JSONToAtoms, but does not abort on the first invalid element.
+//
+// Business logic:
+//   - Handles empty input by returning an empty slice and no errors
+//   - Supports both array of atoms and single atom object
+//   - Elements that cannot be converted are skipped, and an *AtomParseError
+//     holding the element's array index is returned for each of them
+//   - The parsed atoms and the errors both keep the order of the array
+//   - Null array elements are skipped without an error, as in JSONToAtoms
+//   - If the input is not valid JSON at all, no atoms are returned and the
+//     single error is not an *AtomParseError
+//
+// Parameters:
+//   - jsonStr: JSON string containing atom data
+//
+// Returns:
+//   - []AtomInterface: the atoms that could be parsed
+//   - []error: one error per element that could not be parsed
+func ParseAtomsLenient(jsonStr string) ([]AtomInterface, []error) {
+	trimmed := strings.TrimSpace(jsonStr)
+	if trimmed == "" {
+		return []AtomInterface{}, nil
+	}
+
+	var elements []json.RawMessage
+	if trimmed[0] == '[' {
+		if err := json.Unmarshal([]byte(trimmed), &elements); err != nil {
+			return nil, []error{fmt.Errorf("failed to unmarshal JSON: %w", err)}
+		}
+	} else {
+		if !json.Valid([]byte(trimmed)) {
+			return nil, []error{fmt.Errorf("failed to unmarshal JSON: invalid JSON")}
+		}
+		elements = []json.RawMessage{json.RawMessage(trimmed)}
+	}
+
+	atoms := make([]AtomInterface, 0, len(elements))
+	var errs []error
+
+	for i, element := range elements {
+		var atomMap map[string]any
+		if err := json.Unmarshal(element, &atomMap); err != nil {
+			errs = append(errs, &AtomParseError{Index: i, Err: err})
+			continue
+		}
+		if atomMap == nil {
+			continue
+		}
+
+		atom, err := MapToAtom(atomMap)
+		if err != nil {
+			errs = append(errs, &AtomParseError{Index: i, Err: err})
+			continue
+		}
+		atoms = append(atoms, atom)
+	}
+
+	return atoms, errs
+}
@@ -0,0 +1,60 @@
+package omni
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseAtomsLenient_CollectsPerItemErrors(t *testing.T) {
+	jsonStr := `[
+		{"id":"a1","type":"item"},
+		{"id":"a2"},
+		null,
+		42,
+		{"id":"a3","type":"item","properties":{"k":"v"}}
+	]`
+
+	atoms, errs := ParseAtomsLenient(jsonStr)
+
+	if len(atoms) != 2 || atoms[0].GetID() != "a1" || atoms[1].GetID() != "a3" {
+		t.Fatalf("unexpected atoms: %v", atoms)
+	}
+	if atoms[1].Get("k") != "v" {
+		t.Fatal("expected properties to be parsed")
+	}
+
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	for i, wantIndex := range []int{1, 3} {
+		var parseErr *AtomParseError
+		if !errors.As(errs[i], &parseErr) || parseErr.Index != wantIndex {
+			t.Fatalf("errs[%d] = %v, want AtomParseError at index %d", i, errs[i], wantIndex)
+		}
+	}
+}
+
+func TestParseAtomsLenient_SingleObjectAndEmpty(t *testing.T) {
+	atoms, errs := ParseAtomsLenient(`{"id":"a1","type":"item"}`)
+	if len(errs) != 0 || len(atoms) != 1 || atoms[0].GetID() != "a1" {
+		t.Fatalf("single object: atoms = %v, errs = %v", atoms, errs)
+	}
+
+	atoms, errs = ParseAtomsLenient("")
+	if len(errs) != 0 || atoms == nil || len(atoms) != 0 {
+		t.Fatalf("empty input: atoms = %v, errs = %v", atoms, errs)
+	}
+}
+
+func TestParseAtomsLenient_InvalidJSON(t *testing.T) {
+	for _, input := range []string{`[{"id":"a1","type":"item"}`, `{"id":`} {
+		atoms, errs := ParseAtomsLenient(input)
+		if atoms != nil || len(errs) != 1 {
+			t.Fatalf("ParseAtomsLenient(%q) = %v, %v; want nil atoms and one error", input, atoms, errs)
+		}
+		var parseErr *AtomParseError
+		if errors.As(errs[0], &parseErr) {
+			t.Fatalf("expected a whole-input error, got %v", errs[0])
+		}
+	}
+}
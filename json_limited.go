@@ -0,0 +1,104 @@
+package omni
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DefaultMaxAtoms is the number of top-level atoms JSONToAtomsLimited
+// accepts when it is given a non-positive limit.
+const DefaultMaxAtoms = 10000
+
+// JSONToAtomsLimited converts a JSON string to a slice of AtomInterface like
+// JSONToAtoms, but guards against oversized input from untrusted sources.
+//
+// Business logic:
+//   - Handles empty input by returning an empty slice
+//   - Supports both array of atoms and single atom object
+//   - Reads array elements one at a time and stops with an error as soon as
+//     more than maxAtoms top-level atoms are found, without decoding the rest
+//   - Returns an error if the JSON of a single top-level atom (including its
+//     children) is larger than MaxAtomDataSize, the same limit used by GobToAtoms
+//   - A non-positive maxAtoms uses DefaultMaxAtoms
+//   - Null array elements are skipped, as in JSONToAtoms
+//   - Atoms are nested at most DefaultMaxDepth levels deep, as in MapToAtom
+//
+// JSONToAtoms itself applies no count or size limit.
+//
+// Parameters:
+//   - jsonStr: JSON string containing atom data
+//   - maxAtoms: the maximum number of top-level atoms
+//
+// Returns:
+//   - []AtomInterface: slice of parsed atoms
+//   - error: if JSON is invalid, missing required fields or exceeds a limit
+func JSONToAtomsLimited(jsonStr string, maxAtoms int) ([]AtomInterface, error) {
+	if maxAtoms <= 0 {
+		maxAtoms = DefaultMaxAtoms
+	}
+
+	trimmed := strings.TrimSpace(jsonStr)
+	if trimmed == "" {
+		return []AtomInterface{}, nil
+	}
+
+	// A single atom object
+	if trimmed[0] != '[' {
+		if len(trimmed) > MaxAtomDataSize {
+			return nil, fmt.Errorf("atom 0 data size %d exceeds limit %d", len(trimmed), MaxAtomDataSize)
+		}
+		atom, err := JSONToAtom(trimmed)
+		if err != nil {
+			return nil, err
+		}
+		return []AtomInterface{atom}, nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+
+	// Consume the opening bracket
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	atoms := []AtomInterface{}
+	for i := 0; decoder.More(); i++ {
+		if i >= maxAtoms {
+			return nil, fmt.Errorf("too many atoms: limit is %d", maxAtoms)
+		}
+
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal atom %d: %w", i, err)
+		}
+		if len(raw) > MaxAtomDataSize {
+			return nil, fmt.Errorf("atom %d data size %d exceeds limit %d", i, len(raw), MaxAtomDataSize)
+		}
+
+		var atomMap map[string]any
+		if err := json.Unmarshal(raw, &atomMap); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal atom %d: %w", i, err)
+		}
+		if atomMap == nil {
+			continue
+		}
+
+		atom, err := MapToAtom(atomMap)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert map to atom: %w", err)
+		}
+		atoms = append(atoms, atom)
+	}
+
+	// Consume the closing bracket and reject trailing data
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("failed to unmarshal JSON: unexpected data after array")
+	}
+
+	return atoms, nil
+}
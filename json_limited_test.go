@@ -0,0 +1,69 @@
+package omni
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func atomsJSONArray(n int) string {
+	items := make([]string, n)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"id":"a%d","type":"item"}`, i)
+	}
+	return "[" + strings.Join(items, ",") + "]"
+}
+
+func TestJSONToAtomsLimited_WithinLimit(t *testing.T) {
+	atoms, err := JSONToAtomsLimited(atomsJSONArray(3), 3)
+	if err != nil {
+		t.Fatalf("JSONToAtomsLimited() error = %v", err)
+	}
+	if len(atoms) != 3 || atoms[2].GetID() != "a2" {
+		t.Fatalf("unexpected atoms: %v", atoms)
+	}
+
+	single, err := JSONToAtomsLimited(`{"id":"s1","type":"item"}`, 1)
+	if err != nil || len(single) != 1 || single[0].GetID() != "s1" {
+		t.Fatalf("single object: atoms = %v, err = %v", single, err)
+	}
+
+	empty, err := JSONToAtomsLimited("", 1)
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("empty input: atoms = %v, err = %v", empty, err)
+	}
+}
+
+func TestJSONToAtomsLimited_TooManyAtoms(t *testing.T) {
+	_, err := JSONToAtomsLimited(atomsJSONArray(4), 3)
+	if err == nil || !strings.Contains(err.Error(), "too many atoms: limit is 3") {
+		t.Fatalf("JSONToAtomsLimited() error = %v, want too many atoms", err)
+	}
+
+	if _, err := JSONToAtomsLimited(atomsJSONArray(DefaultMaxAtoms+1), 0); err == nil {
+		t.Fatal("expected the default limit to apply for a non-positive maxAtoms")
+	}
+}
+
+func TestJSONToAtomsLimited_AtomTooLarge(t *testing.T) {
+	big := strings.Repeat("x", MaxAtomDataSize)
+	jsonStr := `[{"id":"a1","type":"item","properties":{"big":"` + big + `"}}]`
+
+	_, err := JSONToAtomsLimited(jsonStr, 10)
+	if err == nil || !strings.Contains(err.Error(), "exceeds limit") {
+		t.Fatalf("JSONToAtomsLimited() error = %v, want size limit error", err)
+	}
+}
+
+func TestJSONToAtomsLimited_InvalidJSON(t *testing.T) {
+	for _, input := range []string{
+		`[{"id":"a1","type":"item"}`,
+		`[{"id":"a1","type":"item"}] trailing`,
+		`[{"id":"a1"}]`,
+		`{"id":`,
+	} {
+		if _, err := JSONToAtomsLimited(input, 10); err == nil {
+			t.Errorf("JSONToAtomsLimited(%q) expected error", input)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package omni
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ToMapTyped converts the atom to a map like ToMap, but emits property values
+// that are valid JSON numbers or booleans as typed values instead of strings,
+// recursively for all children.
+//
+// Ambiguity: since all properties are stored as strings, a value that was set
+// as the string "123" (or "true") is also emitted as a number (or boolean).
+// Only values whose text is exactly a JSON number literal ("12", "-1.5e3")
+// or "true"/"false" are converted; anything else (e.g. "007", " 1") stays a string.
+func (a *Atom) ToMapTyped() map[string]any {
+	result := a.ToMap()
+	typeAtomMap(result)
+	return result
+}
+
+// ToJSONTyped converts the atom to a JSON string using ToMapTyped,
+// so numeric and boolean properties are emitted unquoted.
+// Use JSONToAtomTyped to parse the result back.
+func (a *Atom) ToJSONTyped() (string, error) {
+	jsonData, err := json.Marshal(a.ToMapTyped())
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal to typed JSON: %w", err)
+	}
+	return string(jsonData), nil
+}
+
+// JSONToAtomTyped converts a JSON string produced by ToJSONTyped back to an atom.
+//
+// Business logic:
+// - Handles empty input by returning an error
+// - Decodes numbers without converting them to float64, so each numeric
+//   property is restored with exactly the text it was written with
+// - Booleans are restored as "true" or "false"
+// - Converts the JSON object to an Atom using MapToAtom
+//
+// Parameters:
+//   - jsonStr: JSON string containing a single atom's data
+//
+// Returns:
+//   - AtomInterface: the parsed atom
+//   - error: if JSON is invalid or missing required fields
+func JSONToAtomTyped(jsonStr string) (AtomInterface, error) {
+	if jsonStr == "" {
+		return nil, errors.New("empty JSON string provided")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(jsonStr)))
+	decoder.UseNumber()
+
+	var atomMap map[string]any
+	if err := decoder.Decode(&atomMap); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	atom, err := MapToAtom(atomMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid atom data: %w", err)
+	}
+
+	return atom, nil
+}
+
+// typeAtomMap replaces the string properties of an atom map produced by ToMap
+// with typed values, recursively for its children.
+func typeAtomMap(atomMap map[string]any) {
+	if props, ok := atomMap["properties"].(map[string]string); ok {
+		typed := make(map[string]any, len(props))
+		for k, v := range props {
+			typed[k] = typedPropertyValue(v)
+		}
+		atomMap["properties"] = typed
+	}
+
+	children, _ := atomMap["children"].([]any)
+	for _, child := range children {
+		if childMap, ok := child.(map[string]any); ok {
+			typeAtomMap(childMap)
+		}
+	}
+}
+
+// typedPropertyValue returns the value as a bool if it is exactly "true" or "false",
+// as a json.Number if it is exactly a JSON number literal, or unchanged otherwise.
+func typedPropertyValue(value string) any {
+	switch value {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+
+	if isJSONNumber(value) {
+		return json.Number(value)
+	}
+
+	return value
+}
+
+// isJSONNumber reports whether s is exactly a JSON number literal.
+func isJSONNumber(s string) bool {
+	if s == "" || (s[0] != '-' && (s[0] < '0' || s[0] > '9')) {
+		return false
+	}
+	var number json.Number
+	return json.Unmarshal([]byte(s), &number) == nil
+}
@@ -0,0 +1,69 @@
+package omni
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAtom_ToJSONTyped_EmitsNumbersAndBools(t *testing.T) {
+	root := NewAtom("item", WithID("i1"),
+		WithProperty("count", "42"),
+		WithProperty("price", "-1.5e3"),
+		WithProperty("active", "true"),
+		WithProperty("code", "007"),
+		WithProperty("name", "Widget"),
+	)
+	root.ChildAdd(NewAtom("item", WithID("i2"), WithProperty("count", "7")))
+
+	j, err := root.(*Atom).ToJSONTyped()
+	if err != nil {
+		t.Fatalf("ToJSONTyped() error = %v", err)
+	}
+
+	for _, want := range []string{`"count":42`, `"price":-1.5e3`, `"active":true`, `"code":"007"`, `"name":"Widget"`, `"count":7`} {
+		if !strings.Contains(j, want) {
+			t.Fatalf("ToJSONTyped() = %s, want contains %s", j, want)
+		}
+	}
+}
+
+func TestJSONToAtomTyped_RoundTrip(t *testing.T) {
+	root := NewAtom("item", WithID("i1"),
+		WithProperty("big", "12345678901234567890"),
+		WithProperty("million", "1000000"),
+		WithProperty("ratio", "0.10"),
+		WithProperty("active", "false"),
+		WithProperty("name", "Widget"),
+	)
+	root.ChildAdd(NewAtom("item", WithID("i2"), WithProperty("count", "7")))
+
+	j, err := root.(*Atom).ToJSONTyped()
+	if err != nil {
+		t.Fatalf("ToJSONTyped() error = %v", err)
+	}
+
+	parsed, err := JSONToAtomTyped(j)
+	if err != nil {
+		t.Fatalf("JSONToAtomTyped() error = %v", err)
+	}
+
+	want := root.GetAll()
+	got := parsed.GetAll()
+	if len(got) != len(want) {
+		t.Fatalf("got %d properties, want %d", len(got), len(want))
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("property %q = %q, want %q", k, got[k], v)
+		}
+	}
+	if children := parsed.ChildrenGet(); len(children) != 1 || children[0].Get("count") != "7" {
+		t.Fatalf("unexpected children after round trip: %v", children)
+	}
+}
+
+func TestJSONToAtomTyped_EmptyInput(t *testing.T) {
+	if _, err := JSONToAtomTyped(""); err == nil {
+		t.Fatal("expected error for empty input, got nil")
+	}
+}
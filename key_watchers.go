@@ -0,0 +1,112 @@
+package omni
+
+// keyWatcher is a callback registered with OnChangeKey. It is a pointer so
+// its unsubscribe function can find it again.
+type keyWatcher struct {
+	fn func(old, new string)
+}
+
+// keyChange is a change of a watched property's value.
+type keyChange struct {
+	key, old, new string
+}
+
+// OnChangeKey registers fn to be called whenever the value of the property
+// key changes, by any property mutator (Set, Remove, SetAll, Undo, Transact,
+// ...). A missing property counts as the empty string, so writes that leave
+// the value unchanged do not call fn. Several watchers may be registered for
+// the same key; they are called in registration order.
+//
+// Watchers are called after the atom's locks are released, so they may use
+// the atom freely. The returned function unsubscribes fn; it is safe to call
+// more than once, and calling it from fn makes a one-shot watcher.
+func (a *Atom) OnChangeKey(key string, fn func(old, new string)) (unsubscribe func()) {
+	if fn == nil {
+		return func() {}
+	}
+
+	watcher := &keyWatcher{fn: fn}
+
+	a.watchersMu.Lock()
+	if a.keyWatchers == nil {
+		a.keyWatchers = make(map[string][]*keyWatcher)
+	}
+	a.keyWatchers[key] = append(a.keyWatchers[key], watcher)
+	a.watchersMu.Unlock()
+
+	return func() {
+		a.watchersMu.Lock()
+		defer a.watchersMu.Unlock()
+		watchers := a.keyWatchers[key]
+		for i, w := range watchers {
+			if w == watcher {
+				// Copy instead of removing in place, so a notification
+				// iterating over the old slice is not affected.
+				remaining := make([]*keyWatcher, 0, len(watchers)-1)
+				remaining = append(remaining, watchers[:i]...)
+				remaining = append(remaining, watchers[i+1:]...)
+				if len(remaining) == 0 {
+					delete(a.keyWatchers, key)
+				} else {
+					a.keyWatchers[key] = remaining
+				}
+				return
+			}
+		}
+	}
+}
+
+// mutateProperties runs fn while holding the write lock, then calls the key
+// watchers of the properties fn changed, once the lock is released.
+func (a *Atom) mutateProperties(fn func()) {
+	var changes []keyChange
+	func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		before := a.watchedValuesLocked()
+		fn()
+		changes = a.keyChangesLocked(before)
+	}()
+	a.notifyKeyChanges(changes)
+}
+
+// watchedValuesLocked returns the current values of the watched keys,
+// or nil if no key is watched. The caller must hold a.mu.
+func (a *Atom) watchedValuesLocked() map[string]string {
+	a.watchersMu.Lock()
+	defer a.watchersMu.Unlock()
+	if len(a.keyWatchers) == 0 {
+		return nil
+	}
+	values := make(map[string]string, len(a.keyWatchers))
+	for key := range a.keyWatchers {
+		values[key] = a.properties[key]
+	}
+	return values
+}
+
+// keyChangesLocked compares the values recorded by watchedValuesLocked with
+// the current ones. The caller must hold a.mu.
+func (a *Atom) keyChangesLocked(before map[string]string) []keyChange {
+	var changes []keyChange
+	for key, old := range before {
+		if value := a.properties[key]; value != old {
+			changes = append(changes, keyChange{key: key, old: old, new: value})
+		}
+	}
+	return changes
+}
+
+// notifyKeyChanges calls the watchers of each changed key.
+// It must be called without holding any of the atom's locks.
+func (a *Atom) notifyKeyChanges(changes []keyChange) {
+	for _, change := range changes {
+		a.watchersMu.Lock()
+		watchers := a.keyWatchers[change.key]
+		a.watchersMu.Unlock()
+
+		for _, watcher := range watchers {
+			watcher.fn(change.old, change.new)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package omni
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOnChangeKey_FiresOnlyForWatchedKey(t *testing.T) {
+	atom := NewAtom("div").(*Atom)
+
+	var changes [][2]string
+	atom.OnChangeKey("title", func(old, new string) {
+		changes = append(changes, [2]string{old, new})
+	})
+
+	atom.Set("title", "A")
+	atom.Set("other", "x")
+	atom.Set("title", "A") // unchanged value
+	atom.Set("title", "B")
+	atom.Remove("title")
+	atom.Remove("title") // already missing
+
+	want := [][2]string{{"", "A"}, {"A", "B"}, {"B", ""}}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("changes = %v, want %v", changes, want)
+	}
+}
+
+func TestOnChangeKey_BulkMutators(t *testing.T) {
+	atom := NewAtom("div", WithProperty("title", "A"), WithPropertyHistory(5)).(*Atom)
+
+	var changes [][2]string
+	atom.OnChangeKey("title", func(old, new string) {
+		changes = append(changes, [2]string{old, new})
+	})
+
+	atom.SetAll(map[string]string{"title": "B"})
+	atom.Set("title", "C")
+	atom.Undo("title")
+	atom.Transact(func(tx *AtomTx) {
+		tx.Set("title", "D")
+		tx.Set("title", "E")
+	})
+	atom.RemoveAll()
+	atom.SetIfAbsent("title", "F")
+	atom.PropertiesSet([]PropertyInterface{NewProperty("title", "G")})
+	atom.RemoveMatching(func(key string) bool { return key == "title" })
+
+	want := [][2]string{
+		{"A", "B"}, {"B", "C"}, {"C", "B"}, {"B", "E"},
+		{"E", ""}, {"", "F"}, {"F", "G"}, {"G", ""},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("changes = %v, want %v", changes, want)
+	}
+}
+
+func TestOnChangeKey_MultipleWatchersAndUnsubscribe(t *testing.T) {
+	atom := NewAtom("div").(*Atom)
+
+	var first, second int
+	unsubscribe := atom.OnChangeKey("title", func(old, new string) { first++ })
+	atom.OnChangeKey("title", func(old, new string) { second++ })
+
+	atom.Set("title", "A")
+	unsubscribe()
+	unsubscribe() // safe to call twice
+	atom.Set("title", "B")
+
+	if first != 1 || second != 2 {
+		t.Fatalf("first = %d, second = %d, want 1 and 2", first, second)
+	}
+}
+
+func TestOnChangeKey_OneShotWatcher(t *testing.T) {
+	atom := NewAtom("div").(*Atom)
+
+	calls := 0
+	var unsubscribe func()
+	unsubscribe = atom.OnChangeKey("title", func(old, new string) {
+		calls++
+		unsubscribe()
+	})
+
+	atom.Set("title", "A")
+	atom.Set("title", "B")
+
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+}
+
+func TestOnChangeKey_WatcherCanUseAtom(t *testing.T) {
+	atom := NewAtom("div").(*Atom)
+
+	atom.OnChangeKey("title", func(old, new string) {
+		atom.Set("slug", new+"-slug")
+	})
+	atom.Set("title", "home")
+
+	if got := atom.Get("slug"); got != "home-slug" {
+		t.Fatalf("slug = %q, want home-slug", got)
+	}
+}
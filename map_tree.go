@@ -0,0 +1,23 @@
+package omni
+
+// MapTree recursively converts a tree of atoms into a parallel structure of type T.
+// It works bottom-up: the children of each atom are converted first, and fn is then
+// called with the atom and its already-converted children (in order).
+// Nil children are skipped. If root is nil, the zero value of T is returned.
+func MapTree[T any](root AtomInterface, fn func(atom AtomInterface, children []T) T) T {
+	if root == nil {
+		var zero T
+		return zero
+	}
+
+	children := root.ChildrenGet()
+	mapped := make([]T, 0, len(children))
+	for _, child := range children {
+		if child == nil {
+			continue
+		}
+		mapped = append(mapped, MapTree(child, fn))
+	}
+
+	return fn(root, mapped)
+}
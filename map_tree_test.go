@@ -0,0 +1,60 @@
+package omni
+
+import "testing"
+
+type mapTreeNode struct {
+	ID       string
+	Type     string
+	Children []mapTreeNode
+}
+
+func TestMapTree_BuildsNestedStruct(t *testing.T) {
+	root := NewAtom("root", WithID("root"))
+	a := NewAtom("node", WithID("a"))
+	a.ChildAdd(NewAtom("leaf", WithID("a1")))
+	a.ChildAdd(NewAtom("leaf", WithID("a2")))
+	root.ChildAdd(a)
+	root.ChildAdd(NewAtom("node", WithID("b")))
+
+	got := MapTree(root, func(atom AtomInterface, children []mapTreeNode) mapTreeNode {
+		return mapTreeNode{ID: atom.GetID(), Type: atom.GetType(), Children: children}
+	})
+
+	if got.ID != "root" || len(got.Children) != 2 {
+		t.Fatalf("unexpected root: %+v", got)
+	}
+	if got.Children[0].ID != "a" || got.Children[1].ID != "b" {
+		t.Fatalf("unexpected children order: %+v", got.Children)
+	}
+	if len(got.Children[0].Children) != 2 || got.Children[0].Children[1].ID != "a2" {
+		t.Fatalf("unexpected grandchildren: %+v", got.Children[0].Children)
+	}
+	if got.Children[0].Children[0].Type != "leaf" || len(got.Children[1].Children) != 0 {
+		t.Fatalf("unexpected shape: %+v", got)
+	}
+}
+
+func TestMapTree_CountsNodes(t *testing.T) {
+	root := NewAtom("root")
+	root.ChildAdd(NewAtom("a"))
+	root.ChildAdd(NewAtom("b"))
+	root.ChildrenGet()[0].ChildAdd(NewAtom("c"))
+
+	count := MapTree(root, func(_ AtomInterface, children []int) int {
+		total := 1
+		for _, c := range children {
+			total += c
+		}
+		return total
+	})
+	if count != 4 {
+		t.Fatalf("expected 4 nodes, got %d", count)
+	}
+}
+
+func TestMapTree_NilRoot(t *testing.T) {
+	got := MapTree(nil, func(_ AtomInterface, _ []string) string { return "x" })
+	if got != "" {
+		t.Fatalf("expected zero value for nil root, got %q", got)
+	}
+}
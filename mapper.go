@@ -0,0 +1,40 @@
+package omni
+
+import "sync"
+
+// mappers holds the custom map representations registered with RegisterMapper,
+// keyed by atom type.
+var (
+	mappersMu sync.RWMutex
+	mappers   = map[string]func(AtomInterface) map[string]any{}
+)
+
+// RegisterMapper registers fn as the map representation of atoms of the
+// given type. (*Atom).ToMap, and therefore ToJSON, AtomsToJSON and the other
+// helpers built on it, return fn(atom) instead of the default map for atoms
+// of that type, including when they appear as children.
+// Registering a nil fn removes the mapper for the type, restoring the default.
+//
+// The mapper may read the atom through its getters, but must not call ToMap
+// on the same atom, as that would call the mapper again. Note that JSONToAtom
+// and MapToAtom only understand the default representation, so custom maps
+// are generally one-way.
+//
+// The registry is global and safe for concurrent use.
+func RegisterMapper(typeName string, fn func(AtomInterface) map[string]any) {
+	mappersMu.Lock()
+	defer mappersMu.Unlock()
+	if fn == nil {
+		delete(mappers, typeName)
+		return
+	}
+	mappers[typeName] = fn
+}
+
+// lookupMapper returns the mapper registered for the given type, if any.
+func lookupMapper(typeName string) (func(AtomInterface) map[string]any, bool) {
+	mappersMu.RLock()
+	defer mappersMu.RUnlock()
+	fn, ok := mappers[typeName]
+	return fn, ok
+}
@@ -0,0 +1,51 @@
+package omni
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegisterMapper_CustomizesToMap(t *testing.T) {
+	RegisterMapper("date", func(atom AtomInterface) map[string]any {
+		return map[string]any{
+			"id":   atom.GetID(),
+			"date": atom.Get("year") + "-" + atom.Get("month") + "-" + atom.Get("day"),
+		}
+	})
+	t.Cleanup(func() { RegisterMapper("date", nil) })
+
+	event := NewAtom("event", WithID("e1"), WithProperty("name", "Launch"))
+	event.ChildAdd(NewAtom("date", WithID("d1"), WithProperties(map[string]string{
+		"year": "2024", "month": "05", "day": "01",
+	})))
+
+	eventMap := event.ToMap()
+	if _, ok := eventMap["properties"]; !ok {
+		t.Fatal("expected unregistered types to use the default representation")
+	}
+
+	children := eventMap["children"].([]any)
+	if len(children) != 1 || children[0].(map[string]any)["date"] != "2024-05-01" {
+		t.Fatalf("expected the custom child representation, got %v", children)
+	}
+
+	jsonStr, err := event.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON() error = %v", err)
+	}
+	if !strings.Contains(jsonStr, `{"date":"2024-05-01","id":"d1"}`) {
+		t.Fatalf("expected the custom representation in JSON, got %s", jsonStr)
+	}
+}
+
+func TestRegisterMapper_NilRestoresDefault(t *testing.T) {
+	RegisterMapper("date", func(AtomInterface) map[string]any {
+		return map[string]any{"custom": true}
+	})
+	RegisterMapper("date", nil)
+
+	got := NewAtom("date", WithID("d1")).ToMap()
+	if got["id"] != "d1" || got["custom"] != nil {
+		t.Fatalf("expected the default representation, got %v", got)
+	}
+}
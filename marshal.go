@@ -0,0 +1,106 @@
+package omni
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+)
+
+// Compile-time checks that *Atom satisfies the standard encoding interfaces.
+var (
+	_ json.Marshaler             = (*Atom)(nil)
+	_ json.Unmarshaler           = (*Atom)(nil)
+	_ encoding.TextMarshaler     = (*Atom)(nil)
+	_ encoding.TextUnmarshaler   = (*Atom)(nil)
+	_ encoding.BinaryMarshaler   = (*Atom)(nil)
+	_ encoding.BinaryUnmarshaler = (*Atom)(nil)
+)
+
+// MarshalJSON implements json.Marshaler using ToJSON, so an *Atom embedded
+// in another value is encoded as a JSON object.
+func (a *Atom) MarshalJSON() ([]byte, error) {
+	jsonStr, err := a.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(jsonStr), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding JSON as accepted by
+// JSONToAtom. A JSON null leaves the atom unchanged, as encoding/json expects.
+// Otherwise the atom is replaced as by UnmarshalText.
+func (a *Atom) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+	return a.UnmarshalText(data)
+}
+
+// MarshalText implements encoding.TextMarshaler using the JSON encoding
+// of the atom, as produced by ToJSON.
+func (a *Atom) MarshalText() ([]byte, error) {
+	return a.MarshalJSON()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, decoding JSON as
+// accepted by JSONToAtom. The atom's id, type, properties and children are
+// replaced; on error, including a property rejected by the atom's options
+// (see WithKeyValidator), the atom is left unchanged.
+func (a *Atom) UnmarshalText(text []byte) error {
+	decoded, err := JSONToAtom(string(text))
+	if err != nil {
+		return err
+	}
+	atom, ok := AsAtom(decoded)
+	if !ok {
+		atom = copyAtomInterface(decoded)
+	}
+	return a.replaceWith(atom)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler using the gob
+// encoding of the atom, as produced by ToGob.
+func (a *Atom) MarshalBinary() ([]byte, error) {
+	return a.ToGob()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, decoding data
+// produced by MarshalBinary or ToGob with GobToAtomWithMaxDepth, so deeply
+// nested input is rejected. The atom's id, type, properties and children are
+// replaced; on error, including a property rejected by the atom's options,
+// the atom is left unchanged.
+func (a *Atom) UnmarshalBinary(data []byte) error {
+	decoded, err := GobToAtomWithMaxDepth(data, DefaultMaxDepth)
+	if err != nil {
+		return err
+	}
+	atom, ok := AsAtom(decoded)
+	if !ok {
+		atom = copyAtomInterface(decoded)
+	}
+	return a.replaceWith(atom)
+}
+
+// replaceWith replaces the atom's id, type, properties and children with
+// those of src, which must be a freshly decoded atom not shared with anyone
+// else. Only the data is moved, never the locks. The properties go through
+// the atom's property options; if one is rejected, the atom is left unchanged
+// and the error is returned.
+func (a *Atom) replaceWith(src *Atom) error {
+	var err error
+	a.mutateProperties(func() {
+		var props map[string]string
+		if props, err = a.preparePropertiesLocked(src.properties); err != nil {
+			return
+		}
+
+		a.childrenMu.Lock()
+		defer a.childrenMu.Unlock()
+
+		a.id = src.id
+		a.atomType = src.atomType
+		a.properties = props
+		a.setChildrenLocked(src.children)
+	})
+	return err
+}
@@ -0,0 +1,101 @@
+package omni
+
+import (
+	"encoding"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func assertMarshalTree(t *testing.T, got *Atom) {
+	t.Helper()
+	if got.GetID() != "p1" || got.GetType() != "page" || got.Get("title") != "Home" {
+		t.Fatalf("unexpected root: %v", got)
+	}
+	section := got.ChildFindByID("s1")
+	if section == nil || section.Get("name") != "intro" {
+		t.Fatalf("expected section child, got %v", got.ChildrenGet())
+	}
+	if section.ChildFindByID("t1") == nil {
+		t.Fatal("expected grandchild t1")
+	}
+}
+
+func TestAtom_TextMarshalRoundTrip(t *testing.T) {
+	var m encoding.TextMarshaler = newTestTree()
+	text, err := m.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText() error = %v", err)
+	}
+
+	got := &Atom{}
+	if err := got.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText() error = %v", err)
+	}
+	assertMarshalTree(t, got)
+}
+
+func TestAtom_JSONMarshalEmbedded(t *testing.T) {
+	type document struct {
+		Name string
+		Page *Atom
+		None *Atom
+	}
+
+	data, err := json.Marshal(document{Name: "doc", Page: newTestTree()})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"Page":{`) || !strings.Contains(string(data), `"None":null`) {
+		t.Fatalf("expected Page to be encoded as a JSON object, got %s", data)
+	}
+
+	var got document
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	assertMarshalTree(t, got.Page)
+	if got.None != nil {
+		t.Fatalf("expected None to stay nil, got %v", got.None)
+	}
+}
+
+func TestAtom_BinaryMarshalRoundTrip(t *testing.T) {
+	var m encoding.BinaryMarshaler = newTestTree()
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+
+	// Unmarshal into an atom that already has state to replace
+	got := NewAtom("old", WithID("old"), WithProperty("stale", "yes")).(*Atom)
+	got.ChildAdd(NewAtom("old_child"))
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() error = %v", err)
+	}
+	assertMarshalTree(t, got)
+	if got.Has("stale") || got.ChildrenLength() != 2 {
+		t.Fatalf("expected previous state to be replaced, got %v", got)
+	}
+
+	// The decoded atom must remain usable with its own locks
+	got.Set("after", "decode")
+	got.ChildAdd(NewAtom("extra"))
+	if got.Get("after") != "decode" || got.ChildrenLength() != 3 {
+		t.Fatal("expected decoded atom to be mutable")
+	}
+}
+
+func TestAtom_UnmarshalErrorsLeaveAtomUnchanged(t *testing.T) {
+	atom := NewAtom("page", WithID("p1")).(*Atom)
+
+	if err := atom.UnmarshalText([]byte("not json")); err == nil {
+		t.Error("expected UnmarshalText error for invalid JSON")
+	}
+	if err := atom.UnmarshalBinary([]byte("not gob")); err == nil {
+		t.Error("expected UnmarshalBinary error for invalid gob")
+	}
+	if atom.GetID() != "p1" || atom.GetType() != "page" {
+		t.Fatalf("expected atom to be unchanged, got %v", atom)
+	}
+}
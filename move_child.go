@@ -0,0 +1,71 @@
+package omni
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MoveChild re-parents the atom with the given childID, found anywhere in the
+// tree rooted at root, appending it (with its whole subtree) to the children
+// of the atom with the given newParentID.
+//
+// Business logic:
+// - Returns an error if root is nil, or if either ID is not found
+// - Returns an error if the child is the root, as the root has no parent
+// - Returns an error if the move would create a cycle, i.e. the new parent
+//   is the child itself or one of its descendants
+// - The child is removed from its current parent and appended as the last
+//   child of the new parent (even if both parents are the same)
+//
+// Parameters:
+//   - root: the root atom of the tree
+//   - childID: the ID of the atom to move
+//   - newParentID: the ID of the atom to move it under
+//
+// Returns:
+//   - error: if the move is not possible
+func MoveChild(root AtomInterface, childID, newParentID string) error {
+	if root == nil {
+		return errors.New("root atom cannot be nil")
+	}
+
+	if root.GetID() == childID {
+		return fmt.Errorf("cannot move root atom %q", childID)
+	}
+
+	parent, child := findParentAndChild(root, childID)
+	if child == nil {
+		return fmt.Errorf("child atom %q not found", childID)
+	}
+
+	newParent := FindAtomByID(root, newParentID)
+	if newParent == nil {
+		return fmt.Errorf("new parent atom %q not found", newParentID)
+	}
+
+	if FindAtomByID(child, newParentID) != nil {
+		return fmt.Errorf("cannot move atom %q under %q: would create a cycle", childID, newParentID)
+	}
+
+	parent.ChildDeleteByID(childID)
+	newParent.ChildAdd(child)
+
+	return nil
+}
+
+// findParentAndChild finds the first atom with the given ID below atom
+// (in pre-order) and returns it together with its parent.
+func findParentAndChild(atom AtomInterface, id string) (AtomInterface, AtomInterface) {
+	for _, child := range atom.ChildrenGet() {
+		if child == nil {
+			continue
+		}
+		if child.GetID() == id {
+			return atom, child
+		}
+		if parent, found := findParentAndChild(child, id); found != nil {
+			return parent, found
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,66 @@
+package omni
+
+import "testing"
+
+func buildMoveTree() AtomInterface {
+	root := NewAtom("root", WithID("root"))
+	a := NewAtom("container", WithID("a"))
+	item := NewAtom("item", WithID("item"))
+	item.ChildAdd(NewAtom("leaf", WithID("leaf")))
+	a.ChildAdd(item)
+	root.ChildAdd(a)
+	root.ChildAdd(NewAtom("container", WithID("b")))
+	return root
+}
+
+func TestMoveChild_ReparentsWithSubtree(t *testing.T) {
+	root := buildMoveTree()
+
+	if err := MoveChild(root, "item", "b"); err != nil {
+		t.Fatalf("MoveChild() error = %v", err)
+	}
+
+	if FindAtomByID(root, "a").ChildrenLength() != 0 {
+		t.Fatal("expected item to be removed from its old parent")
+	}
+	moved := FindAtomByID(root, "b").ChildFindByID("item")
+	if moved == nil {
+		t.Fatal("expected item under new parent")
+	}
+	if moved.ChildFindByID("leaf") == nil {
+		t.Fatal("expected item subtree to be preserved")
+	}
+}
+
+func TestMoveChild_Errors(t *testing.T) {
+	root := buildMoveTree()
+
+	tests := []struct {
+		name        string
+		childID     string
+		newParentID string
+	}{
+		{"missing child", "missing", "b"},
+		{"missing parent", "item", "missing"},
+		{"move root", "root", "b"},
+		{"move under itself", "item", "item"},
+		{"move under descendant", "a", "leaf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := MoveChild(root, tt.childID, tt.newParentID); err == nil {
+				t.Fatal("expected error, got nil")
+			}
+		})
+	}
+
+	// The tree must be unchanged after failed moves
+	if FindAtomByID(root, "a").ChildFindByID("item") == nil {
+		t.Fatal("expected tree to be unchanged after failed moves")
+	}
+
+	if err := MoveChild(nil, "a", "b"); err == nil {
+		t.Fatal("expected error for nil root, got nil")
+	}
+}
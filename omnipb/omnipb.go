@@ -0,0 +1,241 @@
+// Package omnipb encodes omni atom trees with Protobuf, using the
+// omni.v1.Atom message defined in atom.proto, for interoperability with
+// services written in other languages.
+//
+// The wire format is implemented directly, without generated code or a
+// Protobuf runtime dependency, and is compatible with any Protobuf library
+// using atom.proto.
+package omnipb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/dracory/omni"
+)
+
+// Field numbers of the omni.v1.Atom message and its properties map entries.
+const (
+	fieldID         = 1
+	fieldType       = 2
+	fieldProperties = 3
+	fieldChildren   = 4
+
+	fieldEntryKey   = 1
+	fieldEntryValue = 2
+)
+
+// Protobuf wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// ToProto encodes the tree rooted at atom as an omni.v1.Atom message.
+//
+// Business logic:
+// - Properties named "id" or "type" are not encoded, like in ToMap
+// - Properties are written in key order, so the encoding is deterministic
+// - Nil children are skipped
+// - Returns an error if atom is nil
+//
+// Parameters:
+//   - atom: the root atom of the tree
+//
+// Returns:
+//   - []byte: the encoded message
+//   - error: if atom is nil
+func ToProto(atom omni.AtomInterface) ([]byte, error) {
+	if atom == nil {
+		return nil, errors.New("atom cannot be nil")
+	}
+	return appendAtom(nil, atom), nil
+}
+
+// FromProto decodes an omni.v1.Atom message into an atom tree.
+//
+// Business logic:
+// - Unknown fields are skipped, so messages from newer schemas still decode
+// - Atoms nested more than omni.DefaultMaxDepth levels deep are rejected
+// - Properties named "id" or "type" are ignored
+// - A missing id is kept empty rather than generated
+//
+// Parameters:
+//   - data: the encoded message
+//
+// Returns:
+//   - omni.AtomInterface: the decoded atom
+//   - error: if the data is not a valid message
+func FromProto(data []byte) (omni.AtomInterface, error) {
+	atom, err := decodeAtom(data, 1)
+	if err != nil {
+		return nil, fmt.Errorf("invalid protobuf data: %w", err)
+	}
+	return atom, nil
+}
+
+// appendAtom appends the encoding of atom to buf.
+func appendAtom(buf []byte, atom omni.AtomInterface) []byte {
+	buf = appendString(buf, fieldID, atom.GetID())
+	buf = appendString(buf, fieldType, atom.GetType())
+
+	props := atom.GetAll()
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		if k != "id" && k != "type" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		var entry []byte
+		entry = appendString(entry, fieldEntryKey, k)
+		entry = appendString(entry, fieldEntryValue, props[k])
+		buf = appendBytes(buf, fieldProperties, entry)
+	}
+
+	for _, child := range atom.ChildrenGet() {
+		if child != nil {
+			buf = appendBytes(buf, fieldChildren, appendAtom(nil, child))
+		}
+	}
+
+	return buf
+}
+
+// appendString appends a string field, omitting it if empty as proto3 does.
+func appendString(buf []byte, field int, s string) []byte {
+	if s == "" {
+		return buf
+	}
+	return appendBytes(buf, field, []byte(s))
+}
+
+// appendBytes appends a length-delimited field.
+func appendBytes(buf []byte, field int, b []byte) []byte {
+	buf = binary.AppendUvarint(buf, uint64(field)<<3|wireBytes)
+	buf = binary.AppendUvarint(buf, uint64(len(b)))
+	return append(buf, b...)
+}
+
+// decodeAtom decodes an Atom message found at the given nesting depth.
+func decodeAtom(data []byte, depth int) (omni.AtomInterface, error) {
+	if depth > omni.DefaultMaxDepth {
+		return nil, fmt.Errorf("atom nesting exceeds max depth %d", omni.DefaultMaxDepth)
+	}
+
+	var id, atomType string
+	props := map[string]string{}
+	children := []omni.AtomInterface{}
+
+	err := forEachField(data, func(field int, value []byte) error {
+		switch field {
+		case fieldID:
+			id = string(value)
+		case fieldType:
+			atomType = string(value)
+		case fieldProperties:
+			key, val, err := decodeEntry(value)
+			if err != nil {
+				return fmt.Errorf("properties: %w", err)
+			}
+			props[key] = val
+		case fieldChildren:
+			child, err := decodeAtom(value, depth+1)
+			if err != nil {
+				return fmt.Errorf("child %d: %w", len(children), err)
+			}
+			children = append(children, child)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []omni.AtomOption{omni.WithProperties(props), omni.WithChildren(children...)}
+	if id != "" {
+		return omni.NewAtom(atomType, append(opts, omni.WithID(id))...), nil
+	}
+
+	// NewAtom would generate an id, so build the atom directly to keep it empty
+	atom := &omni.Atom{}
+	for _, opt := range append(opts, omni.WithType(atomType)) {
+		opt(atom)
+	}
+	return atom, nil
+}
+
+// decodeEntry decodes a properties map entry.
+func decodeEntry(data []byte) (string, string, error) {
+	var key, value string
+	err := forEachField(data, func(field int, b []byte) error {
+		switch field {
+		case fieldEntryKey:
+			key = string(b)
+		case fieldEntryValue:
+			value = string(b)
+		}
+		return nil
+	})
+	return key, value, err
+}
+
+// forEachField calls fn with the number and contents of every
+// length-delimited field in data. Fields of other wire types are skipped,
+// as the schema has none; a known field number with the wrong wire type
+// is an error.
+func forEachField(data []byte, fn func(field int, value []byte) error) error {
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errors.New("invalid field tag")
+		}
+		data = data[n:]
+
+		field, wireType := tag>>3, tag&7
+		if field == 0 || field > math.MaxInt32 {
+			return fmt.Errorf("invalid field number %d", field)
+		}
+
+		var size uint64
+		switch wireType {
+		case wireVarint:
+			if _, n = binary.Uvarint(data); n <= 0 {
+				return fmt.Errorf("field %d: invalid varint", field)
+			}
+			size = uint64(n)
+		case wireFixed64:
+			size = 8
+		case wireFixed32:
+			size = 4
+		case wireBytes:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length > uint64(len(data)-n) {
+				return fmt.Errorf("field %d: invalid length", field)
+			}
+			data = data[n:]
+			if err := fn(int(field), data[:length]); err != nil {
+				return err
+			}
+			data = data[length:]
+			continue
+		default:
+			return fmt.Errorf("field %d: unsupported wire type %d", field, wireType)
+		}
+
+		if field <= fieldChildren {
+			return fmt.Errorf("field %d: unexpected wire type %d", field, wireType)
+		}
+		if size > uint64(len(data)) {
+			return fmt.Errorf("field %d: unexpected end of data", field)
+		}
+		data = data[size:]
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package omnipb
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/dracory/omni"
+)
+
+func TestToProto_RoundTrip(t *testing.T) {
+	root := omni.NewAtom("page", omni.WithID("p1"), omni.WithProperties(map[string]string{
+		"title": "Home",
+		"long":  strings.Repeat("x", 300),
+		"empty": "",
+	}))
+	section := omni.NewAtom("section", omni.WithID("s1"))
+	section.ChildAdd(omni.NewAtom("text", omni.WithID("t1"), omni.WithProperty("content", "héllo")))
+	root.ChildAdd(section)
+	footer := omni.NewAtom("footer")
+	footer.SetID("") // a missing id must stay missing
+	root.ChildAdd(footer)
+
+	data, err := ToProto(root)
+	if err != nil {
+		t.Fatalf("ToProto() error = %v", err)
+	}
+
+	decoded, err := FromProto(data)
+	if err != nil {
+		t.Fatalf("FromProto() error = %v", err)
+	}
+	if diff := omni.ExplainDiff(root, decoded); diff != "" {
+		t.Fatalf("round trip changed the atom: %s", diff)
+	}
+
+	again, _ := ToProto(decoded)
+	if !bytes.Equal(data, again) {
+		t.Fatal("expected the encoding to be deterministic")
+	}
+}
+
+func TestFromProto_DoesNotGenerateIDs(t *testing.T) {
+	root := omni.NewAtom("page", omni.WithID("p1"))
+	root.ChildAdd(omni.NewAtom("text", omni.WithID("t1")))
+	footer := omni.NewAtom("footer")
+	footer.SetID("")
+	root.ChildAdd(footer)
+	data, err := ToProto(root)
+	if err != nil {
+		t.Fatalf("ToProto() error = %v", err)
+	}
+
+	calls := 0
+	omni.SetIDGenerator(func() string {
+		calls++
+		return "generated"
+	})
+	t.Cleanup(func() { omni.SetIDGenerator(nil) })
+
+	decoded, err := FromProto(data)
+	if err != nil {
+		t.Fatalf("FromProto() error = %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("FromProto() called the ID generator %d times, want 0", calls)
+	}
+	if diff := omni.ExplainDiff(root, decoded); diff != "" {
+		t.Fatalf("round trip changed the atom: %s", diff)
+	}
+}
+
+func TestToProto_WireFormat(t *testing.T) {
+	atom := omni.NewAtom("t", omni.WithID("a"), omni.WithProperty("k", "v"))
+	atom.ChildAdd(omni.NewAtom("c", omni.WithID("b")))
+
+	data, _ := ToProto(atom)
+
+	// id=1 "a", type=2 "t", properties=3 {1:"k", 2:"v"}, children=4 {id "b", type "c"}
+	want := "0a0161" + "120174" + "1a06" + "0a016b" + "120176" + "2206" + "0a0162" + "120163"
+	if got := hex.EncodeToString(data); got != want {
+		t.Fatalf("ToProto() = %s, want %s", got, want)
+	}
+
+	if _, err := ToProto(nil); err == nil {
+		t.Fatal("expected an error for a nil atom")
+	}
+}
+
+func TestFromProto_SkipsUnknownFields(t *testing.T) {
+	// type "t", then unknown fields 5 (varint), 6 (fixed64), 7 (bytes), 8 (fixed32)
+	data, _ := hex.DecodeString("120174" + "289601" + "310102030405060708" + "3a027879" + "4501020304")
+
+	atom, err := FromProto(data)
+	if err != nil {
+		t.Fatalf("FromProto() error = %v", err)
+	}
+	if atom.GetType() != "t" {
+		t.Fatalf("GetType() = %q, want t", atom.GetType())
+	}
+}
+
+func TestFromProto_Errors(t *testing.T) {
+	tests := map[string]string{
+		"12":     "invalid length",
+		"1205":   "invalid length",
+		"0801":   "unexpected wire type",
+		"ff":     "invalid field tag",
+		"0201":   "invalid field number",
+		"2201ff": "child 0",
+	}
+
+	for input, want := range tests {
+		data, _ := hex.DecodeString(input)
+		_, err := FromProto(data)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("FromProto(%s) error = %v, want %q", input, err, want)
+		}
+	}
+}
+
+func TestFromProto_MaxDepth(t *testing.T) {
+	data := []byte{}
+	for i := 0; i <= omni.DefaultMaxDepth; i++ {
+		data = appendBytes(nil, fieldChildren, data)
+	}
+
+	if _, err := FromProto(data); err == nil || !strings.Contains(err.Error(), "max depth") {
+		t.Fatalf("FromProto() error = %v, want max depth error", err)
+	}
+}
@@ -0,0 +1,134 @@
+package omni
+
+// Parent tracking: whenever an *Atom is added as a child of another *Atom,
+// through any of the child mutators, it records that atom as its parent, and
+// the link is cleared when it is removed. Children that are other
+// AtomInterface implementations, and atoms added to other implementations,
+// are not tracked. An atom added to several parents records the last one.
+
+// Parent returns the *Atom this atom was last added to as a child,
+// or nil if it is a root or was removed from its parent.
+func (a *Atom) Parent() *Atom {
+	a.parentMu.Lock()
+	defer a.parentMu.Unlock()
+	return a.parent
+}
+
+// IsRoot reports whether the atom has no parent (see Parent).
+func (a *Atom) IsRoot() bool {
+	return a.Parent() == nil
+}
+
+// Ancestors returns the chain of parents of the atom, from its immediate
+// parent up to the root, or an empty slice for a root.
+// It relies on parent tracking, so only *Atom parents are followed.
+// The chain stops if it loops back on itself, so cycles are safe.
+func (a *Atom) Ancestors() []AtomInterface {
+	result := []AtomInterface{}
+	seen := map[*Atom]bool{a: true}
+	for parent := a.Parent(); parent != nil && !seen[parent]; parent = parent.Parent() {
+		seen[parent] = true
+		result = append(result, parent)
+	}
+	return result
+}
+
+// IsDescendantOf reports whether other is one of the atom's ancestors, i.e.
+// whether the atom is in the subtree below other. An atom is not its own
+// descendant. It relies on parent tracking, like Ancestors.
+func (a *Atom) IsDescendantOf(other AtomInterface) bool {
+	target, ok := AsAtom(other)
+	if !ok {
+		return false
+	}
+	for _, ancestor := range a.Ancestors() {
+		if ancestor == AtomInterface(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// adopt records a as the parent of child, if child is an *Atom.
+func (a *Atom) adopt(child AtomInterface) {
+	if atom, ok := AsAtom(child); ok {
+		atom.parentMu.Lock()
+		atom.parent = a
+		atom.parentMu.Unlock()
+	}
+}
+
+// release clears the parent of child, if child is an *Atom whose parent is a.
+func (a *Atom) release(child AtomInterface) {
+	if atom, ok := AsAtom(child); ok {
+		atom.parentMu.Lock()
+		if atom.parent == a {
+			atom.parent = nil
+		}
+		atom.parentMu.Unlock()
+	}
+}
+
+// setChildrenLocked replaces the children slice, releasing the previous
+// children and adopting the new ones. The caller must hold childrenMu,
+// or own the atom exclusively.
+func (a *Atom) setChildrenLocked(children []AtomInterface) {
+	for _, child := range a.children {
+		a.release(child)
+	}
+	a.children = children
+	for _, child := range a.children {
+		a.adopt(child)
+	}
+}
+
+// Siblings returns the other children of the atom's parent, in order,
+// excluding the atom itself. It returns an empty slice if the atom has
+// no parent. It relies on parent tracking, like Ancestors.
+func (a *Atom) Siblings() []AtomInterface {
+	result := []AtomInterface{}
+	parent := a.Parent()
+	if parent == nil {
+		return result
+	}
+	for _, child := range parent.ChildrenGet() {
+		if child != nil && child != AtomInterface(a) {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+// NextSibling returns the child following the atom in its parent's children,
+// or nil if the atom is the last child or has no parent.
+func (a *Atom) NextSibling() AtomInterface {
+	return a.siblingAt(1)
+}
+
+// PreviousSibling returns the child preceding the atom in its parent's
+// children, or nil if the atom is the first child or has no parent.
+func (a *Atom) PreviousSibling() AtomInterface {
+	return a.siblingAt(-1)
+}
+
+// siblingAt returns the non-nil child found offset positions away from the
+// atom in its parent's children, taken from a single consistent copy.
+func (a *Atom) siblingAt(offset int) AtomInterface {
+	parent := a.Parent()
+	if parent == nil {
+		return nil
+	}
+	children := parent.ChildrenGet()
+	for i, child := range children {
+		if child != AtomInterface(a) {
+			continue
+		}
+		for j := i + offset; j >= 0 && j < len(children); j += offset {
+			if children[j] != nil {
+				return children[j]
+			}
+		}
+		return nil
+	}
+	return nil
+}
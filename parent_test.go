@@ -0,0 +1,143 @@
+package omni
+
+import "testing"
+
+func TestAtom_ParentTracking(t *testing.T) {
+	root := NewAtom("root", WithID("root")).(*Atom)
+	section := NewAtom("section", WithID("s1")).(*Atom)
+	text := NewAtom("text", WithID("t1")).(*Atom)
+	section.ChildAdd(text)
+	root.ChildAdd(section)
+
+	if text.Parent() != section || section.Parent() != root || root.Parent() != nil {
+		t.Fatal("expected ChildAdd to record parents")
+	}
+
+	root.ChildDeleteByID("s1")
+	if section.Parent() != nil {
+		t.Fatal("expected ChildDeleteByID to clear the parent")
+	}
+
+	root.ChildrenSet([]AtomInterface{section})
+	if section.Parent() != root {
+		t.Fatal("expected ChildrenSet to record the parent")
+	}
+	root.ChildrenSet(nil)
+	if section.Parent() != nil {
+		t.Fatal("expected ChildrenSet to clear the parents of replaced children")
+	}
+
+	other := NewAtom("other").(*Atom)
+	root.ChildAdd(section)
+	other.ChildAdd(section)
+	root.ChildDeleteAllByID("s1")
+	if section.Parent() != other {
+		t.Fatal("expected removal from a previous parent to keep the last parent")
+	}
+}
+
+func TestAtom_ParentTracking_DecodedAndCopiedTrees(t *testing.T) {
+	root := NewAtom("root", WithID("root")).(*Atom)
+	root.ChildAdd(NewAtom("section", WithID("s1")))
+
+	data, err := root.ToGob()
+	if err != nil {
+		t.Fatalf("ToGob() error = %v", err)
+	}
+	decoded, err := FromGob(data)
+	if err != nil {
+		t.Fatalf("FromGob() error = %v", err)
+	}
+
+	snapshot, _ := AsAtom(root.Snapshot())
+	for name, tree := range map[string]*Atom{"decoded": decoded, "snapshot": snapshot} {
+		child, _ := AsAtom(tree.ChildFindByID("s1"))
+		if child == nil || child.Parent() != tree {
+			t.Fatalf("%s: expected children to record their parent", name)
+		}
+	}
+}
+
+func TestAtom_AncestorsAndIsDescendantOf(t *testing.T) {
+	root := NewAtom("root", WithID("root")).(*Atom)
+	section := NewAtom("section", WithID("s1")).(*Atom)
+	text := NewAtom("text", WithID("t1")).(*Atom)
+	section.ChildAdd(text)
+	root.ChildAdd(section)
+
+	ancestors := text.Ancestors()
+	if len(ancestors) != 2 || ancestors[0] != AtomInterface(section) || ancestors[1] != AtomInterface(root) {
+		t.Fatalf("Ancestors() = %v, want [s1 root]", ancestors)
+	}
+	if len(root.Ancestors()) != 0 {
+		t.Fatal("expected a root to have no ancestors")
+	}
+
+	if !text.IsDescendantOf(root) || !text.IsDescendantOf(section) {
+		t.Fatal("expected text to be a descendant of its ancestors")
+	}
+	if text.IsDescendantOf(text) || root.IsDescendantOf(text) || text.IsDescendantOf(nil) {
+		t.Fatal("unexpected descendant relationship")
+	}
+}
+
+func TestAtom_Ancestors_Cycle(t *testing.T) {
+	a := NewAtom("node", WithID("a")).(*Atom)
+	b := NewAtom("node", WithID("b")).(*Atom)
+	a.ChildAdd(b)
+	b.ChildAdd(a)
+
+	if got := a.Ancestors(); len(got) != 1 || got[0] != AtomInterface(b) {
+		t.Fatalf("Ancestors() = %v, want [b]", got)
+	}
+	if !a.IsDescendantOf(b) {
+		t.Fatal("expected a to be a descendant of b")
+	}
+}
+
+func TestAtom_SiblingNavigation(t *testing.T) {
+	parent := NewAtom("list").(*Atom)
+	first := NewAtom("item", WithID("a")).(*Atom)
+	middle := NewAtom("item", WithID("b")).(*Atom)
+	last := NewAtom("item", WithID("c")).(*Atom)
+	parent.ChildAddAll(first, middle, last)
+
+	siblings := middle.Siblings()
+	if len(siblings) != 2 || siblings[0] != AtomInterface(first) || siblings[1] != AtomInterface(last) {
+		t.Fatalf("Siblings() = %v, want [a c]", siblings)
+	}
+
+	if middle.NextSibling() != AtomInterface(last) || middle.PreviousSibling() != AtomInterface(first) {
+		t.Fatal("unexpected next/previous sibling of the middle child")
+	}
+	if last.NextSibling() != nil || first.PreviousSibling() != nil {
+		t.Fatal("expected nil at the ends")
+	}
+
+	orphan := NewAtom("item").(*Atom)
+	if len(orphan.Siblings()) != 0 || orphan.NextSibling() != nil || orphan.PreviousSibling() != nil {
+		t.Fatal("expected no siblings without a parent")
+	}
+}
+
+func TestAtom_IsLeafAndIsRoot(t *testing.T) {
+	root := NewAtom("page").(*Atom)
+	child := NewAtom("text").(*Atom)
+
+	if !root.IsLeaf() || !root.IsRoot() {
+		t.Fatal("a new atom should be a leaf and a root")
+	}
+
+	root.ChildAdd(child)
+	if root.IsLeaf() || !root.IsRoot() {
+		t.Fatal("a parent should be a root but not a leaf")
+	}
+	if !child.IsLeaf() || child.IsRoot() {
+		t.Fatal("a child without children should be a leaf but not a root")
+	}
+
+	root.ChildDeleteByID(child.GetID())
+	if !root.IsLeaf() || !child.IsRoot() {
+		t.Fatal("removing the child should make both leaves and roots again")
+	}
+}
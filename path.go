@@ -0,0 +1,145 @@
+package omni
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GetByPath returns the value found at path in the tree rooted at root.
+//
+// A path is a slash-delimited list of segments, in the spirit of JSON pointers:
+//   - "children/N" selects the child at index N
+//   - "#ID" selects the immediate child with the given ID
+//   - "properties/KEY" selects a property and must end the path
+//   - "id" and "type" select the atom's ID and type and must end the path
+//
+// For example "children/0/#intro/properties/title". A leading slash is
+// optional, and "~1" and "~0" in a segment stand for "/" and "~".
+//
+// Parameters:
+//   - root: the root atom of the tree
+//   - path: the path of the value
+//
+// Returns:
+//   - string: the value
+//   - error: if the path is invalid or does not resolve to a value
+func GetByPath(root AtomInterface, path string) (string, error) {
+	atom, field, key, err := resolvePath(root, path)
+	if err != nil {
+		return "", err
+	}
+
+	switch field {
+	case "id":
+		return atom.GetID(), nil
+	case "type":
+		return atom.GetType(), nil
+	}
+
+	if !atom.Has(key) {
+		return "", fmt.Errorf("path %q: property %q not found", path, key)
+	}
+	return atom.Get(key), nil
+}
+
+// SetByPath sets the value found at path in the tree rooted at root,
+// using the path syntax of GetByPath. Setting a missing property creates it.
+// Properties of *Atom targets are set with SetChecked, so a write rejected
+// by the atom's options is reported as an error.
+//
+// Parameters:
+//   - root: the root atom of the tree
+//   - path: the path of the value
+//   - value: the value to set
+//
+// Returns:
+//   - error: if the path is invalid, does not resolve to a value, or the
+//     write is rejected
+func SetByPath(root AtomInterface, path, value string) error {
+	atom, field, key, err := resolvePath(root, path)
+	if err != nil {
+		return err
+	}
+
+	switch field {
+	case "id":
+		atom.SetID(value)
+	case "type":
+		atom.SetType(value)
+	default:
+		if target, ok := AsAtom(atom); ok {
+			return target.SetChecked(key, value)
+		}
+		atom.Set(key, value)
+	}
+	return nil
+}
+
+// pathSegmentEscaper and pathSegmentUnescaper encode and decode
+// the "~1" and "~0" escapes in path segments.
+var (
+	pathSegmentEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pathSegmentUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// resolvePath walks path from root and returns the atom it ends on, the
+// selected field ("id", "type" or "properties") and, for properties, the key.
+func resolvePath(root AtomInterface, path string) (AtomInterface, string, string, error) {
+	if root == nil {
+		return nil, "", "", errors.New("root atom cannot be nil")
+	}
+
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i := range segments {
+		segments[i] = pathSegmentUnescaper.Replace(segments[i])
+	}
+
+	atom := root
+	for i := 0; i < len(segments); i++ {
+		segment := segments[i]
+		last := i == len(segments)-1
+
+		switch {
+		case segment == "id" || segment == "type":
+			if !last {
+				return nil, "", "", fmt.Errorf("path %q: %q must be the last segment", path, segment)
+			}
+			return atom, segment, "", nil
+
+		case segment == "properties":
+			if i+2 != len(segments) {
+				return nil, "", "", fmt.Errorf("path %q: \"properties\" must be followed by exactly one key", path)
+			}
+			return atom, segment, segments[i+1], nil
+
+		case segment == "children":
+			if last {
+				return nil, "", "", fmt.Errorf("path %q: \"children\" must be followed by an index", path)
+			}
+			i++
+			index, err := strconv.Atoi(segments[i])
+			if err != nil || index < 0 {
+				return nil, "", "", fmt.Errorf("path %q: invalid child index %q", path, segments[i])
+			}
+			children := atom.ChildrenGet()
+			if index >= len(children) || children[index] == nil {
+				return nil, "", "", fmt.Errorf("path %q: child index %d out of range", path, index)
+			}
+			atom = children[index]
+
+		case strings.HasPrefix(segment, "#") && len(segment) > 1:
+			child := atom.ChildFindByID(segment[1:])
+			if child == nil {
+				return nil, "", "", fmt.Errorf("path %q: child %q not found", path, segment[1:])
+			}
+			atom = child
+
+		default:
+			return nil, "", "", fmt.Errorf("path %q: unexpected segment %q", path, segment)
+		}
+	}
+
+	return nil, "", "", fmt.Errorf("path %q: does not end with a property, \"id\" or \"type\"", path)
+}
@@ -0,0 +1,96 @@
+package omni
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetByPath(t *testing.T) {
+	root := newTestTree()
+
+	tests := map[string]string{
+		"properties/title":                  "Home",
+		"/properties/title":                 "Home",
+		"id":                                "p1",
+		"children/0/type":                   "section",
+		"#s1/children/0/properties/content": "Hello",
+		"children/0/#t1/id":                 "t1",
+		"properties/a~1b":                   "x",
+	}
+
+	for path, want := range tests {
+		got, err := GetByPath(root, path)
+		if err != nil {
+			t.Fatalf("GetByPath(%q) error = %v", path, err)
+		}
+		if got != want {
+			t.Fatalf("GetByPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestGetByPath_Errors(t *testing.T) {
+	root := newTestTree()
+
+	tests := map[string]string{
+		"":                       "unexpected segment",
+		"children":               "must be followed by an index",
+		"children/x/id":          "invalid child index",
+		"children/5/id":          "out of range",
+		"#missing/id":            "not found",
+		"properties":             "exactly one key",
+		"properties/title/extra": "exactly one key",
+		"properties/missing":     `property "missing" not found`,
+		"id/extra":               "must be the last segment",
+		"children/0":             "does not end with",
+		"unknown":                "unexpected segment",
+	}
+
+	for path, want := range tests {
+		_, err := GetByPath(root, path)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("GetByPath(%q) error = %v, want %q", path, err, want)
+		}
+	}
+
+	if _, err := GetByPath(nil, "id"); err == nil {
+		t.Fatal("expected an error for a nil root")
+	}
+}
+
+func TestSetByPath(t *testing.T) {
+	root := newTestTree()
+
+	if err := SetByPath(root, "#s1/children/0/properties/content", "Bye"); err != nil {
+		t.Fatalf("SetByPath() error = %v", err)
+	}
+	if err := SetByPath(root, "children/1/properties/new", "created"); err != nil {
+		t.Fatalf("SetByPath() error = %v", err)
+	}
+	if err := SetByPath(root, "children/1/type", "nav"); err != nil {
+		t.Fatalf("SetByPath() error = %v", err)
+	}
+
+	if got, _ := GetByPath(root, "#s1/#t1/properties/content"); got != "Bye" {
+		t.Fatalf("content = %q, want Bye", got)
+	}
+	text := root.ChildFindByID("t2")
+	if text.Get("new") != "created" || text.GetType() != "nav" {
+		t.Fatalf("unexpected t2 after SetByPath: %v", text.ToMap())
+	}
+
+	if err := SetByPath(root, "children/9/id", "x"); err == nil {
+		t.Fatal("expected an error for an invalid path")
+	}
+}
+
+func TestSetByPath_RejectedWrite(t *testing.T) {
+	root := NewAtom("page", WithID("p1"), WithMaxValueLength(3, RejectLongValues))
+
+	if err := SetByPath(root, "properties/title", "too long"); err == nil {
+		t.Fatal("expected an error for a value rejected by the atom")
+	}
+	if root.Has("title") {
+		t.Fatal("expected the rejected property not to be set")
+	}
+}
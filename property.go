@@ -0,0 +1,100 @@
+package omni
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Property is the main implementation of PropertyInterface.
+// It holds a name and a string value and is safe for concurrent use.
+//
+// Typed values (int, bool, time.Time) are stored in their canonical
+// string form, so GetValue always works regardless of how a value was set.
+// Times use the RFC 3339 format with nanoseconds.
+type Property struct {
+	name  string
+	value string
+	mu    sync.RWMutex
+}
+
+// NewProperty creates a new Property with the given name and value.
+func NewProperty(name, value string) PropertyInterface {
+	return &Property{name: name, value: value}
+}
+
+// GetName returns the property's name.
+func (p *Property) GetName() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.name
+}
+
+// SetName sets the property's name.
+func (p *Property) SetName(name string) PropertyInterface {
+	p.mu.Lock()
+	p.name = name
+	p.mu.Unlock()
+	return p
+}
+
+// GetValue returns the property's value.
+func (p *Property) GetValue() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.value
+}
+
+// SetValue sets the property's value.
+func (p *Property) SetValue(value string) PropertyInterface {
+	p.mu.Lock()
+	p.value = value
+	p.mu.Unlock()
+	return p
+}
+
+// GetValueInt parses the property's value as an int.
+func (p *Property) GetValueInt() (int, error) {
+	value := p.GetValue()
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("property %q value %q is not an int: %w", p.GetName(), value, err)
+	}
+	return i, nil
+}
+
+// SetValueInt stores the int value in its canonical string form.
+func (p *Property) SetValueInt(value int) PropertyInterface {
+	return p.SetValue(strconv.Itoa(value))
+}
+
+// GetValueBool parses the property's value as a bool.
+func (p *Property) GetValueBool() (bool, error) {
+	value := p.GetValue()
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("property %q value %q is not a bool: %w", p.GetName(), value, err)
+	}
+	return b, nil
+}
+
+// SetValueBool stores the bool value in its canonical string form.
+func (p *Property) SetValueBool(value bool) PropertyInterface {
+	return p.SetValue(strconv.FormatBool(value))
+}
+
+// GetValueTime parses the property's value as an RFC 3339 time.
+func (p *Property) GetValueTime() (time.Time, error) {
+	value := p.GetValue()
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("property %q value %q is not an RFC 3339 time: %w", p.GetName(), value, err)
+	}
+	return t, nil
+}
+
+// SetValueTime stores the time value in RFC 3339 format with nanoseconds.
+func (p *Property) SetValueTime(value time.Time) PropertyInterface {
+	return p.SetValue(value.Format(time.RFC3339Nano))
+}
@@ -0,0 +1,68 @@
+package omni
+
+// WithPropertyHistory enables per-property history tracking on the Atom.
+// Each call to Set on an existing key pushes the previous value onto that
+// key's history stack, keeping at most limit values (the oldest are dropped).
+// A limit of zero or less disables history tracking.
+func WithPropertyHistory(limit int) AtomOption {
+	return func(a *Atom) {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.historyLimit = limit
+		if limit > 0 && a.history == nil {
+			a.history = make(map[string][]string)
+		}
+	}
+}
+
+// History returns a copy of the previous values of the given key,
+// from oldest to newest. It returns an empty slice if history tracking
+// is disabled or the key has no history.
+func (a *Atom) History(key string) []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	values := a.history[key]
+	result := make([]string, len(values))
+	copy(result, values)
+	return result
+}
+
+// Undo restores the most recent previous value of the given key,
+// removing it from the history. It returns false if there is
+// no history for the key.
+func (a *Atom) Undo(key string) bool {
+	undone := false
+	a.mutateProperties(func() {
+		values := a.history[key]
+		if len(values) == 0 {
+			return
+		}
+
+		last := values[len(values)-1]
+		if len(values) == 1 {
+			delete(a.history, key)
+		} else {
+			a.history[key] = values[:len(values)-1]
+		}
+
+		if a.properties == nil {
+			a.properties = make(map[string]string)
+		}
+		a.properties[key] = last
+		undone = true
+	})
+	return undone
+}
+
+// pushHistory records a previous value for the key, if history tracking
+// is enabled. The caller must hold the write lock.
+func (a *Atom) pushHistory(key, value string) {
+	if a.historyLimit <= 0 {
+		return
+	}
+	values := append(a.history[key], value)
+	if len(values) > a.historyLimit {
+		values = values[len(values)-a.historyLimit:]
+	}
+	a.history[key] = values
+}
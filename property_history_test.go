@@ -0,0 +1,53 @@
+package omni
+
+import "testing"
+
+func TestPropertyHistory_TracksAndUndoes(t *testing.T) {
+	a := NewAtom("test", WithPropertyHistory(10)).(*Atom)
+	a.Set("title", "v1")
+	a.Set("title", "v2")
+	a.Set("title", "v3")
+
+	history := a.History("title")
+	if len(history) != 2 || history[0] != "v1" || history[1] != "v2" {
+		t.Fatalf("unexpected history: %v", history)
+	}
+
+	if !a.Undo("title") || a.Get("title") != "v2" {
+		t.Fatalf("expected undo to restore v2, got %q", a.Get("title"))
+	}
+	if !a.Undo("title") || a.Get("title") != "v1" {
+		t.Fatalf("expected undo to restore v1, got %q", a.Get("title"))
+	}
+	if a.Undo("title") {
+		t.Fatal("expected undo to return false with empty history")
+	}
+	if a.Get("title") != "v1" {
+		t.Fatalf("expected value to stay v1, got %q", a.Get("title"))
+	}
+}
+
+func TestPropertyHistory_RespectsLimit(t *testing.T) {
+	a := NewAtom("test", WithPropertyHistory(2)).(*Atom)
+	for _, v := range []string{"a", "b", "c", "d"} {
+		a.Set("key", v)
+	}
+
+	history := a.History("key")
+	if len(history) != 2 || history[0] != "b" || history[1] != "c" {
+		t.Fatalf("expected history [b c], got %v", history)
+	}
+}
+
+func TestPropertyHistory_DisabledByDefault(t *testing.T) {
+	a := NewAtom("test").(*Atom)
+	a.Set("key", "a")
+	a.Set("key", "b")
+
+	if history := a.History("key"); len(history) != 0 {
+		t.Fatalf("expected no history by default, got %v", history)
+	}
+	if a.Undo("key") {
+		t.Fatal("expected undo to return false when history is disabled")
+	}
+}
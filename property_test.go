@@ -0,0 +1,109 @@
+package omni
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProperty_GetSet(t *testing.T) {
+	p := NewProperty("name", "Alice")
+	if p.GetName() != "name" || p.GetValue() != "Alice" {
+		t.Fatalf("unexpected property: %s=%s", p.GetName(), p.GetValue())
+	}
+
+	p.SetName("title").SetValue("Boss")
+	if p.GetName() != "title" || p.GetValue() != "Boss" {
+		t.Fatalf("unexpected property after set: %s=%s", p.GetName(), p.GetValue())
+	}
+}
+
+func TestAtom_PropertiesGet_SortedByName(t *testing.T) {
+	a := NewAtom("test", WithProperty("b", "2"), WithProperty("c", "3"), WithProperty("a", "1")).(*Atom)
+
+	props := a.PropertiesGet()
+	if len(props) != 3 {
+		t.Fatalf("expected 3 properties, got %d", len(props))
+	}
+	for i, want := range []string{"a", "b", "c"} {
+		if props[i].GetName() != want {
+			t.Fatalf("property %d: expected %q, got %q", i, want, props[i].GetName())
+		}
+	}
+	if props[1].GetValue() != "2" {
+		t.Fatalf("expected value 2 for b, got %q", props[1].GetValue())
+	}
+
+	// Modifying the returned properties must not affect the atom
+	props[0].SetValue("changed")
+	if a.Get("a") != "1" {
+		t.Fatalf("expected atom property unchanged, got %q", a.Get("a"))
+	}
+}
+
+func TestAtom_PropertiesSet(t *testing.T) {
+	a := NewAtom("test", WithProperty("old", "x")).(*Atom)
+	a.PropertiesSet([]PropertyInterface{
+		NewProperty("name", "Alice"),
+		nil,
+		NewProperty("role", "admin"),
+	})
+
+	if a.Has("old") {
+		t.Fatal("expected PropertiesSet to replace existing properties")
+	}
+	if a.Get("name") != "Alice" || a.Get("role") != "admin" {
+		t.Fatalf("unexpected properties: %v", a.GetAll())
+	}
+	if len(a.GetAll()) != 2 {
+		t.Fatalf("expected 2 properties, got %d", len(a.GetAll()))
+	}
+}
+
+func TestProperty_TypedInt(t *testing.T) {
+	p := NewProperty("count", "")
+	p.SetValueInt(42)
+	if p.GetValue() != "42" {
+		t.Fatalf("expected canonical string 42, got %q", p.GetValue())
+	}
+	if got, err := p.GetValueInt(); err != nil || got != 42 {
+		t.Fatalf("GetValueInt() = %d, %v", got, err)
+	}
+
+	p.SetValue("not-a-number")
+	if _, err := p.GetValueInt(); err == nil {
+		t.Fatal("expected error parsing invalid int")
+	}
+}
+
+func TestProperty_TypedBool(t *testing.T) {
+	p := NewProperty("enabled", "")
+	p.SetValueBool(true)
+	if p.GetValue() != "true" {
+		t.Fatalf("expected canonical string true, got %q", p.GetValue())
+	}
+	if got, err := p.GetValueBool(); err != nil || !got {
+		t.Fatalf("GetValueBool() = %v, %v", got, err)
+	}
+
+	p.SetValue("maybe")
+	if _, err := p.GetValueBool(); err == nil {
+		t.Fatal("expected error parsing invalid bool")
+	}
+}
+
+func TestProperty_TypedTime(t *testing.T) {
+	when := time.Date(2024, 5, 6, 7, 8, 9, 123, time.UTC)
+	p := NewProperty("created", "")
+	p.SetValueTime(when)
+	if p.GetValue() != "2024-05-06T07:08:09.000000123Z" {
+		t.Fatalf("unexpected canonical time string %q", p.GetValue())
+	}
+	if got, err := p.GetValueTime(); err != nil || !got.Equal(when) {
+		t.Fatalf("GetValueTime() = %v, %v", got, err)
+	}
+
+	p.SetValue("yesterday")
+	if _, err := p.GetValueTime(); err == nil {
+		t.Fatal("expected error parsing invalid time")
+	}
+}
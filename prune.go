@@ -0,0 +1,51 @@
+package omni
+
+// Prune removes from the tree rooted at root every atom satisfying pred,
+// together with its whole subtree.
+//
+// Business logic:
+// - Returns 0 if root or pred is nil
+// - The root itself is never removed, even if it satisfies pred
+// - Atoms are checked top-down; descendants of a removed atom are not visited
+// - Parents whose children did not change are left untouched
+//
+// Parameters:
+//   - root: the root atom of the tree
+//   - pred: reports whether an atom should be removed
+//
+// Returns:
+//   - int: the number of atoms removed from their parents (subtree roots)
+func Prune(root AtomInterface, pred func(AtomInterface) bool) int {
+	if root == nil || pred == nil {
+		return 0
+	}
+	return pruneChildren(root, pred)
+}
+
+// pruneChildren removes the matching children of atom and recurses into
+// the remaining ones, returning the number of atoms removed.
+func pruneChildren(atom AtomInterface, pred func(AtomInterface) bool) int {
+	children := atom.ChildrenGet()
+	kept := make([]AtomInterface, 0, len(children))
+	removed := 0
+
+	for _, child := range children {
+		if child != nil && pred(child) {
+			removed++
+			continue
+		}
+		kept = append(kept, child)
+	}
+
+	if removed > 0 {
+		atom.ChildrenSet(kept)
+	}
+
+	for _, child := range kept {
+		if child != nil {
+			removed += pruneChildren(child, pred)
+		}
+	}
+
+	return removed
+}
@@ -0,0 +1,60 @@
+package omni
+
+import "testing"
+
+func buildPruneTree() AtomInterface {
+	root := NewAtom("root", WithID("root"), WithProperty("deleted", "true"))
+	a := NewAtom("section", WithID("a"))
+	a.ChildAdd(NewAtom("item", WithID("a1"), WithProperty("deleted", "true")))
+	a.ChildAdd(NewAtom("item", WithID("a2")))
+	b := NewAtom("section", WithID("b"), WithProperty("deleted", "true"))
+	b.ChildAdd(NewAtom("item", WithID("b1"), WithProperty("deleted", "true")))
+	root.ChildAdd(a)
+	root.ChildAdd(b)
+	return root
+}
+
+func isDeleted(atom AtomInterface) bool {
+	return atom.Get("deleted") == "true"
+}
+
+func TestPrune_RemovesMatchingSubtrees(t *testing.T) {
+	root := buildPruneTree()
+
+	if got := Prune(root, isDeleted); got != 2 {
+		t.Fatalf("Prune() = %d, want 2", got)
+	}
+
+	if FindAtomByID(root, "a1") != nil {
+		t.Error("expected a1 to be removed")
+	}
+	if FindAtomByID(root, "b") != nil || FindAtomByID(root, "b1") != nil {
+		t.Error("expected b and its subtree to be removed")
+	}
+	if FindAtomByID(root, "a2") == nil {
+		t.Error("expected a2 to be kept")
+	}
+}
+
+func TestPrune_KeepsRoot(t *testing.T) {
+	root := buildPruneTree()
+
+	Prune(root, func(AtomInterface) bool { return false })
+	if root.ChildrenLength() != 2 {
+		t.Fatalf("expected no changes, got %d children", root.ChildrenLength())
+	}
+
+	Prune(root, isDeleted)
+	if root.GetID() != "root" || root.ChildrenLength() != 1 {
+		t.Fatalf("expected root to be kept with one child, got %d", root.ChildrenLength())
+	}
+}
+
+func TestPrune_NilInputs(t *testing.T) {
+	if got := Prune(nil, isDeleted); got != 0 {
+		t.Fatalf("Prune(nil) = %d, want 0", got)
+	}
+	if got := Prune(NewAtom("root"), nil); got != 0 {
+		t.Fatalf("Prune(nil pred) = %d, want 0", got)
+	}
+}
@@ -0,0 +1,139 @@
+package omni
+
+import "iter"
+
+// ReadOnlyAtom is a read-only view of an atom, returned by AsReadOnly, for
+// passing trees to code that must not modify them, such as untrusted plugins.
+//
+// Unlike a snapshot, the view is not a copy: it reads the live atom, so it
+// sees later changes made by the atom's owner. It has no mutators and never
+// exposes the underlying atom: children and found atoms are returned as
+// read-only views too, and maps and slices are copies. This is enforced by
+// the type, not by a flag that could be turned off.
+type ReadOnlyAtom struct {
+	atom AtomInterface
+}
+
+// AsReadOnly returns a read-only view of the atom and its subtree.
+func (a *Atom) AsReadOnly() *ReadOnlyAtom {
+	return &ReadOnlyAtom{atom: a}
+}
+
+// readOnly wraps atom in a read-only view, or returns nil for a nil atom.
+func readOnly(atom AtomInterface) *ReadOnlyAtom {
+	if isNilAtom(atom) {
+		return nil
+	}
+	return &ReadOnlyAtom{atom: atom}
+}
+
+// readOnlyAll wraps each non-nil atom in a read-only view.
+func readOnlyAll(atoms []AtomInterface) []*ReadOnlyAtom {
+	result := make([]*ReadOnlyAtom, 0, len(atoms))
+	for _, atom := range atoms {
+		if view := readOnly(atom); view != nil {
+			result = append(result, view)
+		}
+	}
+	return result
+}
+
+// GetID returns the atom's ID.
+func (r *ReadOnlyAtom) GetID() string {
+	return r.atom.GetID()
+}
+
+// GetType returns the atom's type.
+func (r *ReadOnlyAtom) GetType() string {
+	return r.atom.GetType()
+}
+
+// Get returns the value for the given key, or "" if not found.
+func (r *ReadOnlyAtom) Get(key string) string {
+	return r.atom.Get(key)
+}
+
+// Has checks if the atom has a property with the given key.
+func (r *ReadOnlyAtom) Has(key string) bool {
+	return r.atom.Has(key)
+}
+
+// GetAll returns a copy of all properties of the atom.
+func (r *ReadOnlyAtom) GetAll() map[string]string {
+	return r.atom.GetAll()
+}
+
+// ChildrenLength returns the number of children.
+func (r *ReadOnlyAtom) ChildrenLength() int {
+	return r.atom.ChildrenLength()
+}
+
+// ChildrenGet returns read-only views of the immediate children.
+func (r *ReadOnlyAtom) ChildrenGet() []*ReadOnlyAtom {
+	return readOnlyAll(r.atom.ChildrenGet())
+}
+
+// Children returns an iterator over read-only views of the immediate children.
+func (r *ReadOnlyAtom) Children() iter.Seq[*ReadOnlyAtom] {
+	return func(yield func(*ReadOnlyAtom) bool) {
+		for _, child := range r.ChildrenGet() {
+			if !yield(child) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over read-only views of the atom and all its
+// descendants, in pre-order, like (*Atom).All.
+func (r *ReadOnlyAtom) All() iter.Seq[*ReadOnlyAtom] {
+	return func(yield func(*ReadOnlyAtom) bool) {
+		allAtoms(r.atom, func(atom AtomInterface) bool {
+			return yield(readOnly(atom))
+		})
+	}
+}
+
+// ChildFindByID returns a read-only view of the first immediate child with
+// the given ID, or nil if not found.
+func (r *ReadOnlyAtom) ChildFindByID(id string) *ReadOnlyAtom {
+	return readOnly(r.atom.ChildFindByID(id))
+}
+
+// ChildrenFindByType returns read-only views of all immediate children
+// with the given type.
+func (r *ReadOnlyAtom) ChildrenFindByType(atomType string) []*ReadOnlyAtom {
+	return readOnlyAll(r.atom.ChildrenFindByType(atomType))
+}
+
+// FindByID returns a read-only view of the first atom with the given ID in
+// the subtree, including the atom itself, or nil if not found.
+func (r *ReadOnlyAtom) FindByID(id string) *ReadOnlyAtom {
+	return readOnly(FindAtomByID(r.atom, id))
+}
+
+// FindAllByType returns read-only views of all atoms with the given type in
+// the subtree, including the atom itself, in pre-order.
+func (r *ReadOnlyAtom) FindAllByType(atomType string) []*ReadOnlyAtom {
+	return readOnlyAll(FindAtomsByType(r.atom, atomType))
+}
+
+// ToMap converts the atom to a map, like (*Atom).ToMap.
+func (r *ReadOnlyAtom) ToMap() map[string]any {
+	return r.atom.ToMap()
+}
+
+// ToJSON converts the atom to a JSON string.
+func (r *ReadOnlyAtom) ToJSON() (string, error) {
+	return r.atom.ToJSON()
+}
+
+// ToJSONPretty converts the atom to an indented JSON string.
+func (r *ReadOnlyAtom) ToJSONPretty() (string, error) {
+	return r.atom.ToJSONPretty()
+}
+
+// ToGob encodes the atom using the gob package.
+func (r *ReadOnlyAtom) ToGob() ([]byte, error) {
+	return r.atom.ToGob()
+}
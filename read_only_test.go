@@ -0,0 +1,76 @@
+package omni
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadOnlyAtom_Reads(t *testing.T) {
+	root := newTestTree()
+	view := root.AsReadOnly()
+
+	if view.GetID() != "p1" || view.GetType() != "page" || view.Get("title") != "Home" || !view.Has("title") {
+		t.Fatal("unexpected getter results")
+	}
+	if view.ChildrenLength() != 2 || len(view.ChildrenGet()) != 2 {
+		t.Fatal("expected two children")
+	}
+	if found := view.FindByID("t1"); found == nil || found.GetType() != "text" {
+		t.Fatalf("FindByID(t1) = %v", found)
+	}
+	if view.FindByID("missing") != nil || view.ChildFindByID("t1") != nil {
+		t.Fatal("expected nil for atoms that are not found")
+	}
+	if got := view.ChildrenFindByType("text"); len(got) != 1 || got[0].GetID() != "t2" {
+		t.Fatalf("ChildrenFindByType(text) = %v", got)
+	}
+	if got := view.FindAllByType("text"); len(got) != 2 {
+		t.Fatalf("FindAllByType(text) = %v", got)
+	}
+
+	var ids []string
+	for atom := range view.All() {
+		ids = append(ids, atom.GetID())
+	}
+	if got := strings.Join(ids, ","); got != "p1,s1,t1,t2" {
+		t.Fatalf("All() visited %s", got)
+	}
+
+	want, _ := root.ToJSON()
+	if got, _ := view.ToJSON(); got != want {
+		t.Fatalf("ToJSON() = %s, want %s", got, want)
+	}
+}
+
+func TestReadOnlyAtom_CannotMutate(t *testing.T) {
+	root := newTestTree()
+	view := root.AsReadOnly()
+
+	props := view.GetAll()
+	props["title"] = "changed"
+	if root.Get("title") != "Home" {
+		t.Fatal("GetAll must return a copy")
+	}
+
+	// The view and its children expose no mutators
+	viewType := reflect.TypeOf(view)
+	for _, name := range []string{"Set", "SetID", "SetAll", "Remove", "ChildAdd", "ChildrenSet"} {
+		if _, ok := viewType.MethodByName(name); ok {
+			t.Fatalf("ReadOnlyAtom must not have a %s method", name)
+		}
+	}
+	if _, ok := any(view).(AtomInterface); ok {
+		t.Fatal("ReadOnlyAtom must not implement AtomInterface")
+	}
+}
+
+func TestReadOnlyAtom_IsLiveView(t *testing.T) {
+	root := newTestTree()
+	view := root.AsReadOnly()
+
+	root.Set("title", "Updated")
+	if view.Get("title") != "Updated" {
+		t.Fatal("the view should see changes made by the owner")
+	}
+}
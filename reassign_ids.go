@@ -0,0 +1,48 @@
+package omni
+
+// ReassignIDs gives every atom in the tree rooted at root a fresh ID, for
+// example before importing a copied subtree into a document where its IDs
+// could collide.
+//
+// Business logic:
+//   - Returns an empty map if root is nil
+//   - Atoms are visited in pre-order, and each gets the next ID from gen
+//   - If gen is nil, the package ID generator is used (see SetIDGenerator)
+//   - Parent/child relationships are structural and are not affected
+//   - If several atoms share an old ID, the mapping keeps the new ID of the
+//     first one found in pre-order
+//
+// Parameters:
+//   - root: the root atom of the tree
+//   - gen: generates a new ID on each call
+//
+// Returns:
+//   - map[string]string: the old ID to new ID mapping, to rewrite any ID
+//     references stored in property values
+func ReassignIDs(root AtomInterface, gen func() string) map[string]string {
+	mapping := map[string]string{}
+	if root == nil {
+		return mapping
+	}
+	if gen == nil {
+		gen = generateID
+	}
+	reassignIDs(root, gen, mapping)
+	return mapping
+}
+
+// reassignIDs assigns new IDs to atom and its descendants in pre-order.
+func reassignIDs(atom AtomInterface, gen func() string, mapping map[string]string) {
+	oldID := atom.GetID()
+	newID := gen()
+	atom.SetID(newID)
+	if _, exists := mapping[oldID]; !exists {
+		mapping[oldID] = newID
+	}
+
+	for _, child := range atom.ChildrenGet() {
+		if child != nil {
+			reassignIDs(child, gen, mapping)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package omni
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestReassignIDs(t *testing.T) {
+	root := NewAtom("root", WithID("root"))
+	section := NewAtom("section", WithID("s1"), WithProperty("ref", "t1"))
+	section.ChildAdd(NewAtom("text", WithID("t1")))
+	root.ChildAdd(section)
+	root.ChildAdd(NewAtom("text", WithID("t1")))
+
+	n := 0
+	mapping := ReassignIDs(root, func() string {
+		n++
+		return "new-" + strconv.Itoa(n)
+	})
+
+	want := map[string]string{"root": "new-1", "s1": "new-2", "t1": "new-3"}
+	if len(mapping) != len(want) {
+		t.Fatalf("mapping = %v, want %v", mapping, want)
+	}
+	for oldID, newID := range want {
+		if mapping[oldID] != newID {
+			t.Fatalf("mapping = %v, want %v", mapping, want)
+		}
+	}
+
+	if root.GetID() != "new-1" || root.ChildFindByID("new-4") == nil {
+		t.Fatal("expected every atom to get a new ID")
+	}
+	moved := root.ChildFindByID("new-2")
+	if moved == nil || moved.ChildFindByID("new-3") == nil {
+		t.Fatal("expected the tree structure to be preserved")
+	}
+	if mapping[moved.Get("ref")] != "new-3" {
+		t.Fatal("expected the mapping to resolve ID references in properties")
+	}
+}
+
+func TestReassignIDs_NilRootAndDefaultGenerator(t *testing.T) {
+	if got := ReassignIDs(nil, nil); got == nil || len(got) != 0 {
+		t.Fatalf("ReassignIDs(nil) = %v, want empty map", got)
+	}
+
+	atom := NewAtom("item", WithID("old"))
+	mapping := ReassignIDs(atom, nil)
+	if atom.GetID() == "old" || mapping["old"] != atom.GetID() {
+		t.Fatalf("expected the default generator to assign a new ID, got %q", atom.GetID())
+	}
+}
@@ -0,0 +1,68 @@
+package omni
+
+import (
+	"errors"
+	"strings"
+)
+
+// RenderHTML renders the tree rooted at root to HTML using caller-supplied renderers.
+//
+// Business logic:
+// - renderers maps an atom type to a function producing the HTML for an atom,
+//   given the atom and the already-rendered HTML of its children (concatenated in order)
+// - Atoms whose type has no renderer render their children only
+// - Nil children are skipped
+// - No specific tags are baked in, and no escaping is done: renderers are
+//   responsible for escaping property values (e.g. with html.EscapeString)
+//
+// Parameters:
+//   - root: the root atom of the tree to render
+//   - renderers: map of atom type to render function
+//
+// Returns:
+//   - string: the rendered HTML
+//   - error: if root is nil
+func RenderHTML(root AtomInterface, renderers map[string]func(AtomInterface, string) string) (string, error) {
+	if root == nil {
+		return "", errors.New("root atom cannot be nil")
+	}
+	return renderWith(root, renderers), nil
+}
+
+// RenderMarkdown renders the tree rooted at root to Markdown using caller-supplied renderers.
+// It mirrors RenderHTML: renderers maps an atom type to a function producing the
+// Markdown for an atom, given the atom and the already-rendered Markdown of its children.
+// Atoms whose type has no renderer concatenate their children's output.
+//
+// Parameters:
+//   - root: the root atom of the tree to render
+//   - renderers: map of atom type to render function
+//
+// Returns:
+//   - string: the rendered Markdown
+//   - error: if root is nil
+func RenderMarkdown(root AtomInterface, renderers map[string]func(AtomInterface, string) string) (string, error) {
+	if root == nil {
+		return "", errors.New("root atom cannot be nil")
+	}
+	return renderWith(root, renderers), nil
+}
+
+// renderWith renders the atom bottom-up: children are rendered first, then
+// passed to the renderer registered for the atom's type. If no renderer is
+// registered, the rendered children are returned as is.
+func renderWith(atom AtomInterface, renderers map[string]func(AtomInterface, string) string) string {
+	var children strings.Builder
+	for _, child := range atom.ChildrenGet() {
+		if child != nil {
+			children.WriteString(renderWith(child, renderers))
+		}
+	}
+
+	render, ok := renderers[atom.GetType()]
+	if !ok || render == nil {
+		return children.String()
+	}
+
+	return render(atom, children.String())
+}
@@ -0,0 +1,121 @@
+package omni
+
+import (
+	"fmt"
+	"html"
+	"testing"
+)
+
+func testHTMLRenderers() map[string]func(AtomInterface, string) string {
+	return map[string]func(AtomInterface, string) string{
+		"page": func(a AtomInterface, children string) string {
+			return "<main>" + children + "</main>"
+		},
+		"header": func(a AtomInterface, children string) string {
+			return fmt.Sprintf("<h1>%s</h1>", html.EscapeString(a.Get("text")))
+		},
+		"list": func(a AtomInterface, children string) string {
+			return "<ul>" + children + "</ul>"
+		},
+		"item": func(a AtomInterface, children string) string {
+			return fmt.Sprintf("<li>%s</li>", html.EscapeString(a.Get("text")))
+		},
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	page := NewAtom("page")
+	page.ChildAdd(NewAtom("header", WithProperty("text", "Fish & Chips")))
+	list := NewAtom("list")
+	list.ChildAdd(NewAtom("item", WithProperty("text", "one")))
+	list.ChildAdd(NewAtom("item", WithProperty("text", "two")))
+	page.ChildAdd(list)
+
+	got, err := RenderHTML(page, testHTMLRenderers())
+	if err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+
+	want := "<main><h1>Fish &amp; Chips</h1><ul><li>one</li><li>two</li></ul></main>"
+	if got != want {
+		t.Fatalf("RenderHTML() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderHTML_UnknownTypeRendersChildrenOnly(t *testing.T) {
+	wrapper := NewAtom("unknown")
+	wrapper.ChildAdd(NewAtom("item", WithProperty("text", "a")))
+	wrapper.ChildAdd(NewAtom("item", WithProperty("text", "b")))
+
+	got, err := RenderHTML(wrapper, testHTMLRenderers())
+	if err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	if want := "<li>a</li><li>b</li>"; got != want {
+		t.Fatalf("RenderHTML() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderHTML_NilRoot(t *testing.T) {
+	if _, err := RenderHTML(nil, testHTMLRenderers()); err == nil {
+		t.Fatal("expected error for nil root, got nil")
+	}
+}
+
+func TestRenderMarkdown_UnknownTypeConcatenatesChildren(t *testing.T) {
+	doc := NewAtom("document")
+	doc.ChildAdd(NewAtom("paragraph", WithProperty("text", "one")))
+	doc.ChildAdd(NewAtom("paragraph", WithProperty("text", "two")))
+
+	renderers := map[string]func(AtomInterface, string) string{
+		"paragraph": func(a AtomInterface, _ string) string {
+			return a.Get("text") + "\n\n"
+		},
+	}
+
+	got, err := RenderMarkdown(doc, renderers)
+	if err != nil {
+		t.Fatalf("RenderMarkdown() error = %v", err)
+	}
+	if want := "one\n\ntwo\n\n"; got != want {
+		t.Fatalf("RenderMarkdown() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderMarkdown_NilRoot(t *testing.T) {
+	if _, err := RenderMarkdown(nil, nil); err == nil {
+		t.Fatal("expected error for nil root, got nil")
+	}
+}
+
+func ExampleRenderMarkdown() {
+	doc := NewAtom("document")
+	doc.ChildAdd(NewAtom("heading", WithProperty("text", "Shopping")))
+	doc.ChildAdd(NewAtom("paragraph", WithProperty("text", "Things to buy:")))
+	list := NewAtom("list")
+	list.ChildAdd(NewAtom("item", WithProperty("text", "Milk")))
+	list.ChildAdd(NewAtom("item", WithProperty("text", "Bread")))
+	doc.ChildAdd(list)
+
+	renderers := map[string]func(AtomInterface, string) string{
+		"heading": func(a AtomInterface, _ string) string {
+			return "# " + a.Get("text") + "\n\n"
+		},
+		"paragraph": func(a AtomInterface, _ string) string {
+			return a.Get("text") + "\n\n"
+		},
+		"item": func(a AtomInterface, _ string) string {
+			return "- " + a.Get("text") + "\n"
+		},
+	}
+
+	markdown, _ := RenderMarkdown(doc, renderers)
+	fmt.Print(markdown)
+	// Output:
+	// # Shopping
+	//
+	// Things to buy:
+	//
+	// - Milk
+	// - Bread
+}
@@ -0,0 +1,51 @@
+package omni
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RenderTree renders the tree rooted at root as an indented text tree,
+// which is useful for debugging and examples.
+//
+// Each atom is rendered on its own line as "- id (type: type)", followed by
+// its properties as `key: "value"` lines sorted by key, then its children
+// indented by two more spaces. Nil atoms are skipped, so a nil root renders
+// as an empty string.
+//
+// Example output:
+//
+//	- book1 (type: book)
+//	    title: "The Art of Go"
+//	  - page1 (type: page)
+//	      number: "1"
+func RenderTree(root AtomInterface) string {
+	var sb strings.Builder
+	renderTreeNode(&sb, root, 0)
+	return sb.String()
+}
+
+// renderTreeNode writes the atom and its descendants to sb at the given depth.
+func renderTreeNode(sb *strings.Builder, atom AtomInterface, depth int) {
+	if atom == nil {
+		return
+	}
+
+	indent := strings.Repeat("  ", depth)
+	fmt.Fprintf(sb, "%s- %s (type: %s)\n", indent, atom.GetID(), atom.GetType())
+
+	props := atom.GetAll()
+	keys := make([]string, 0, len(props))
+	for key := range props {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(sb, "%s    %s: %q\n", indent, key, props[key])
+	}
+
+	for _, child := range atom.ChildrenGet() {
+		renderTreeNode(sb, child, depth+1)
+	}
+}
@@ -0,0 +1,29 @@
+package omni
+
+import "testing"
+
+func TestRenderTree(t *testing.T) {
+	root := NewAtom("book", WithID("book1"), WithProperty("title", "Go"), WithProperty("author", "Ann"))
+	page := NewAtom("page", WithID("page1"), WithProperty("number", "1"))
+	page.ChildAdd(NewAtom("text", WithID("t1")))
+	root.ChildAdd(page)
+	root.ChildAdd(NewAtom("page", WithID("page2")))
+
+	want := "- book1 (type: book)\n" +
+		"    author: \"Ann\"\n" +
+		"    title: \"Go\"\n" +
+		"  - page1 (type: page)\n" +
+		"      number: \"1\"\n" +
+		"    - t1 (type: text)\n" +
+		"  - page2 (type: page)\n"
+
+	if got := RenderTree(root); got != want {
+		t.Fatalf("RenderTree() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderTree_NilRoot(t *testing.T) {
+	if got := RenderTree(nil); got != "" {
+		t.Fatalf("expected empty string for nil root, got %q", got)
+	}
+}
@@ -0,0 +1,94 @@
+package omni
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// TypeSpec describes the rules for atoms of a given type.
+type TypeSpec struct {
+	// RequiredProperties lists the property keys every atom of the type must have.
+	RequiredProperties []string
+
+	// AllowedChildTypes lists the types allowed for immediate children.
+	// If empty, children of any type are allowed.
+	AllowedChildTypes []string
+}
+
+// Schema is a registry of TypeSpec rules by atom type,
+// used by ValidateTree to check trees, e.g. at import time.
+// Atoms whose type is not registered are not checked.
+type Schema struct {
+	types map[string]TypeSpec
+	mu    sync.RWMutex
+}
+
+// NewSchema creates a new, empty Schema.
+func NewSchema() *Schema {
+	return &Schema{types: make(map[string]TypeSpec)}
+}
+
+// RegisterType registers the rules for atoms of the given type,
+// replacing any rules previously registered for it.
+func (s *Schema) RegisterType(typeName string, spec TypeSpec) *Schema {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.types[typeName] = spec
+	return s
+}
+
+// TypeSpec returns the rules registered for the given type,
+// and whether the type is registered.
+func (s *Schema) TypeSpec(typeName string) (TypeSpec, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	spec, ok := s.types[typeName]
+	return spec, ok
+}
+
+// ValidateTree checks every atom in the tree rooted at root against the schema
+// and returns all violations found, in pre-order. Each error names the
+// offending atom ID and the rule broken. It returns nil if the tree is valid.
+func ValidateTree(root AtomInterface, schema *Schema) []error {
+	if root == nil {
+		return []error{errors.New("root atom cannot be nil")}
+	}
+	if schema == nil {
+		return []error{errors.New("schema cannot be nil")}
+	}
+
+	var errs []error
+	validateAtomAgainstSchema(root, schema, &errs)
+	return errs
+}
+
+// validateAtomAgainstSchema appends the violations of the atom and its descendants to errs.
+func validateAtomAgainstSchema(atom AtomInterface, schema *Schema, errs *[]error) {
+	children := atom.ChildrenGet()
+
+	if spec, ok := schema.TypeSpec(atom.GetType()); ok {
+		for _, key := range spec.RequiredProperties {
+			if !atom.Has(key) {
+				*errs = append(*errs, fmt.Errorf("atom %q of type %q: missing required property %q",
+					atom.GetID(), atom.GetType(), key))
+			}
+		}
+
+		if len(spec.AllowedChildTypes) > 0 {
+			for _, child := range children {
+				if child != nil && !slices.Contains(spec.AllowedChildTypes, child.GetType()) {
+					*errs = append(*errs, fmt.Errorf("atom %q of type %q: child %q has disallowed type %q",
+						atom.GetID(), atom.GetType(), child.GetID(), child.GetType()))
+				}
+			}
+		}
+	}
+
+	for _, child := range children {
+		if child != nil {
+			validateAtomAgainstSchema(child, schema, errs)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package omni
+
+import (
+	"strings"
+	"testing"
+)
+
+func testSchema() *Schema {
+	return NewSchema().
+		RegisterType("form", TypeSpec{AllowedChildTypes: []string{"button", "input"}}).
+		RegisterType("button", TypeSpec{RequiredProperties: []string{"label"}})
+}
+
+func TestValidateTree_Valid(t *testing.T) {
+	form := NewAtom("form", WithID("f1"))
+	form.ChildAdd(NewAtom("button", WithID("b1"), WithProperty("label", "OK")))
+	form.ChildAdd(NewAtom("input", WithID("i1")))
+
+	if errs := ValidateTree(form, testSchema()); len(errs) != 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+}
+
+func TestValidateTree_CollectsAllViolations(t *testing.T) {
+	form := NewAtom("form", WithID("f1"))
+	form.ChildAdd(NewAtom("button", WithID("b1")))
+	form.ChildAdd(NewAtom("image", WithID("img1")))
+	form.ChildAdd(NewAtom("button", WithID("b2")))
+
+	errs := ValidateTree(form, testSchema())
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 errors, got %d: %v", len(errs), errs)
+	}
+
+	want := []string{
+		`atom "f1" of type "form": child "img1" has disallowed type "image"`,
+		`atom "b1" of type "button": missing required property "label"`,
+		`atom "b2" of type "button": missing required property "label"`,
+	}
+	for i := range want {
+		if !strings.Contains(errs[i].Error(), want[i]) {
+			t.Fatalf("error %d = %q, want %q", i, errs[i], want[i])
+		}
+	}
+}
+
+func TestValidateTree_NilArguments(t *testing.T) {
+	if errs := ValidateTree(nil, testSchema()); len(errs) != 1 {
+		t.Fatalf("expected 1 error for nil root, got %v", errs)
+	}
+	if errs := ValidateTree(NewAtom("form"), nil); len(errs) != 1 {
+		t.Fatalf("expected 1 error for nil schema, got %v", errs)
+	}
+}
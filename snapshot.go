@@ -0,0 +1,122 @@
+package omni
+
+// Snapshot returns a deep copy of the atom and its whole subtree, taken at a
+// single point in time. Readers can traverse the snapshot freely while a writer
+// keeps mutating the live tree.
+//
+// Consistency: read locks are acquired on every *Atom in the subtree before
+// anything is copied, and released only after the copy is complete, so the
+// snapshot never mixes states from before and after a concurrent write.
+// Children that are other AtomInterface implementations are copied through
+// their interface methods, without this guarantee.
+//
+// The snapshot shares no state with the live tree. It is a regular atom and
+// is not enforced to be immutable, so treat it as read-only by convention.
+// Pass it to Restore to roll the atom back to this point.
+func (a *Atom) Snapshot() AtomInterface {
+	locked := []*Atom{}
+	a.rlockTree(map[*Atom]bool{}, &locked)
+	defer func() {
+		for _, atom := range locked {
+			atom.childrenMu.RUnlock()
+			atom.mu.RUnlock()
+		}
+	}()
+
+	return a.copyLocked()
+}
+
+// Restore rolls the atom back to a snapshot taken with Snapshot, replacing its
+// id, type, properties and children with a deep copy of the snapshot's.
+// Since the snapshot is copied, later mutations of the atom never leak into it,
+// and the same snapshot can be restored several times. Options such as
+// WithPropertyHistory or WithUniqueChildIDs are kept, and the restored
+// properties go through the property options like any other write; a
+// snapshot with a property they reject is not restored. A nil snapshot is a no-op.
+func (a *Atom) Restore(snapshot AtomInterface) AtomInterface {
+	if isNilAtom(snapshot) {
+		return a
+	}
+
+	var restored *Atom
+	if atom, ok := AsAtom(snapshot); ok {
+		restored, _ = AsAtom(atom.Snapshot())
+	} else {
+		restored = copyAtomInterface(snapshot)
+	}
+
+	_ = a.replaceWith(restored)
+	return a
+}
+
+// rlockTree acquires the read locks of the atom and all *Atom descendants,
+// recording them in locked. Atoms that appear more than once are locked once.
+func (a *Atom) rlockTree(seen map[*Atom]bool, locked *[]*Atom) {
+	if seen[a] {
+		return
+	}
+	seen[a] = true
+
+	a.mu.RLock()
+	a.childrenMu.RLock()
+	*locked = append(*locked, a)
+
+	for _, child := range a.children {
+		if atom, ok := AsAtom(child); ok {
+			atom.rlockTree(seen, locked)
+		}
+	}
+}
+
+// copyLocked deep copies the atom, whose locks (and those of its
+// *Atom descendants) must already be held by the caller.
+func (a *Atom) copyLocked() *Atom {
+	properties := make(map[string]string, len(a.properties))
+	for k, v := range a.properties {
+		properties[k] = v
+	}
+
+	children := make([]AtomInterface, 0, len(a.children))
+	for _, child := range a.children {
+		if child == nil {
+			continue
+		}
+		if atom, ok := AsAtom(child); ok {
+			children = append(children, atom.copyLocked())
+		} else {
+			children = append(children, copyAtomInterface(child))
+		}
+	}
+
+	result := &Atom{
+		id:         a.id,
+		atomType:   a.atomType,
+		properties: properties,
+	}
+	result.setChildrenLocked(children)
+	return result
+}
+
+// copyAtomInterface deep copies any AtomInterface implementation
+// into an *Atom, using only the interface methods.
+func copyAtomInterface(atom AtomInterface) *Atom {
+	children := make([]AtomInterface, 0, atom.ChildrenLength())
+	for _, child := range atom.ChildrenGet() {
+		if child != nil {
+			children = append(children, copyAtomInterface(child))
+		}
+	}
+
+	properties := atom.GetAll()
+	if properties == nil {
+		properties = make(map[string]string)
+	}
+
+	result := &Atom{
+		id:         atom.GetID(),
+		atomType:   atom.GetType(),
+		properties: properties,
+	}
+	result.setChildrenLocked(children)
+	return result
+}
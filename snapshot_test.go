@@ -0,0 +1,122 @@
+package omni
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestAtom_Snapshot_IsIndependentDeepCopy(t *testing.T) {
+	root := NewAtom("root", WithID("root"), WithProperty("title", "v1"))
+	child := NewAtom("node", WithID("c1"), WithProperty("name", "child"))
+	child.ChildAdd(NewAtom("leaf", WithID("l1")))
+	root.ChildAdd(child)
+
+	snap := root.(*Atom).Snapshot()
+
+	// Mutate the live tree
+	root.Set("title", "v2")
+	child.Set("name", "changed")
+	child.ChildAdd(NewAtom("leaf", WithID("l2")))
+	root.ChildAdd(NewAtom("node", WithID("c2")))
+
+	if snap.Get("title") != "v1" {
+		t.Fatalf("snapshot title changed: %q", snap.Get("title"))
+	}
+	snapChildren := snap.ChildrenGet()
+	if len(snapChildren) != 1 || snapChildren[0].Get("name") != "child" {
+		t.Fatalf("snapshot children changed: %v", snapChildren)
+	}
+	if snapChildren[0] == child {
+		t.Fatal("snapshot must not share child atoms with the live tree")
+	}
+	if snapChildren[0].ChildrenLength() != 1 {
+		t.Fatalf("snapshot grandchildren changed: %d", snapChildren[0].ChildrenLength())
+	}
+}
+
+func TestAtom_Snapshot_CopiesOtherImplementations(t *testing.T) {
+	root := NewAtom("root", WithID("root"))
+	root.ChildAdd(customAtom{AtomInterface: NewAtom("custom", WithID("x"), WithProperty("k", "v"))})
+
+	snap := root.(*Atom).Snapshot()
+	children := snap.ChildrenGet()
+	if len(children) != 1 || children[0].GetID() != "x" || children[0].Get("k") != "v" {
+		t.Fatalf("unexpected snapshot of custom child: %v", children)
+	}
+	if _, ok := AsAtom(children[0]); !ok {
+		t.Fatal("expected custom child to be copied into an *Atom")
+	}
+}
+
+func TestAtom_Snapshot_ConcurrentWriter(t *testing.T) {
+	root := NewAtom("root", WithID("root"))
+	for i := 0; i < 10; i++ {
+		root.ChildAdd(NewAtom("node", WithID(fmt.Sprintf("c%d", i))))
+	}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			for _, child := range root.ChildrenGet() {
+				child.Set("gen", fmt.Sprint(i))
+			}
+			root.ChildAdd(NewAtom("node"))
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		snap := root.(*Atom).Snapshot()
+		if snap.ChildrenLength() < 10 {
+			t.Fatalf("snapshot lost children: %d", snap.ChildrenLength())
+		}
+	}
+
+	close(stop)
+	wg.Wait()
+}
+
+func TestAtom_Restore(t *testing.T) {
+	a := NewAtom("doc", WithID("d1"), WithProperty("title", "v1")).(*Atom)
+	a.ChildAdd(NewAtom("section", WithID("s1")))
+
+	checkpoint := a.Snapshot()
+
+	a.Set("title", "v2")
+	a.SetType("draft")
+	a.ChildAdd(NewAtom("section", WithID("s2")))
+	a.ChildFindByID("s1").Set("edited", "yes")
+
+	a.Restore(checkpoint)
+
+	if a.GetType() != "doc" || a.Get("title") != "v1" {
+		t.Fatalf("expected properties to be restored, got %v", a)
+	}
+	if ids := a.ChildIDs(); len(ids) != 1 || ids[0] != "s1" {
+		t.Fatalf("expected children to be restored, got %v", ids)
+	}
+	if a.ChildFindByID("s1").Has("edited") {
+		t.Fatal("expected mutations of descendants to be rolled back")
+	}
+
+	// Mutations after a restore must not leak into the snapshot
+	a.Set("title", "v3")
+	a.ChildFindByID("s1").Set("edited", "again")
+	a.Restore(checkpoint)
+	if a.Get("title") != "v1" || a.ChildFindByID("s1").Has("edited") {
+		t.Fatal("expected the snapshot to be reusable")
+	}
+
+	a.Restore(nil)
+	if a.Get("title") != "v1" {
+		t.Fatal("expected Restore(nil) to be a no-op")
+	}
+}
@@ -0,0 +1,242 @@
+package omni
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// structTagName is the struct tag naming the property of a field,
+// such as `omni:"title"`. The tag `omni:"-"` skips the field.
+const structTagName = "omni"
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// SetFromStruct sets a property for each exported field of the struct v,
+// or of the struct v points to.
+//
+// Business logic:
+// - The property is named by the field's `omni:"name"` tag, or else the field name
+// - Fields tagged `omni:"-"` and unexported fields are skipped
+// - Fields of embedded structs are set as if they belonged to v
+// - Strings are set as is; bools, numbers and time.Duration use their canonical form
+// - time.Time uses RFC 3339 with nanoseconds, like Property
+// - Nil pointer fields are skipped, other pointers are dereferenced
+// - Any other field type returns an error, and no property is set
+//
+// Parameters:
+//   - v: a struct or a pointer to a struct
+//
+// Returns:
+//   - error: if v is not a struct, a field is unsupported or a property is rejected
+func (a *Atom) SetFromStruct(v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return errors.New("SetFromStruct: nil pointer")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("SetFromStruct: expected a struct, got %T", v)
+	}
+
+	props := map[string]string{}
+	keys := []string{}
+	err := forEachStructField(rv, func(name string, field reflect.Value) error {
+		for field.Kind() == reflect.Pointer {
+			if field.IsNil() {
+				return nil
+			}
+			field = field.Elem()
+		}
+		value, err := formatStructField(field)
+		if err != nil {
+			return fmt.Errorf("SetFromStruct: field %q: %w", name, err)
+		}
+		if _, exists := props[name]; !exists {
+			keys = append(keys, name)
+		}
+		props[name] = value
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	a.mutateProperties(func() {
+		for _, key := range keys {
+			if err = a.setLocked(key, props[key]); err != nil {
+				err = fmt.Errorf("SetFromStruct: %w", err)
+				return
+			}
+		}
+	})
+	return err
+}
+
+// forEachStructField calls fn with the property name and value of each
+// exported field of the struct rv, descending into embedded structs.
+func forEachStructField(rv reflect.Value, fn func(name string, field reflect.Value) error) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag := sf.Tag.Get(structTagName)
+		if tag == "-" {
+			continue
+		}
+
+		if sf.Anonymous && tag == "" && sf.Type.Kind() == reflect.Struct {
+			if err := forEachStructField(rv.Field(i), fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+
+		name := tag
+		if name == "" {
+			name = sf.Name
+		}
+		if err := fn(name, rv.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatStructField returns the canonical string form of a field value.
+func formatStructField(field reflect.Value) (string, error) {
+	switch field.Type() {
+	case timeType:
+		if !field.CanInterface() {
+			return "", errors.New("unexported time.Time value")
+		}
+		return field.Interface().(time.Time).Format(time.RFC3339Nano), nil
+	case durationType:
+		return time.Duration(field.Int()).String(), nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		return field.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(field.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(field.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(field.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(field.Float(), 'f', -1, field.Type().Bits()), nil
+	default:
+		return "", fmt.Errorf("unsupported type %s", field.Type())
+	}
+}
+
+// DecodeInto reads the atom's properties into the fields of the struct v
+// points to. It is the inverse of SetFromStruct and uses the same field names.
+//
+// Business logic:
+// - Properties are read at once, so the fields reflect a single state
+// - Fields without a matching property are left unchanged (zero for a new struct)
+// - Values are parsed into the field type, the inverse of SetFromStruct
+// - Nil pointer fields are allocated as needed
+// - Unparsable values and unsupported field types with a property are errors
+// - All errors are collected and returned joined
+//
+// Parameters:
+//   - v: a non-nil pointer to a struct
+//
+// Returns:
+//   - error: if v is not a pointer to a struct, or any field could not be set
+func (a *Atom) DecodeInto(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("DecodeInto: expected a non-nil pointer to a struct, got %T", v)
+	}
+
+	props := a.GetAll()
+	var errs []error
+	_ = forEachStructField(rv.Elem(), func(name string, field reflect.Value) error {
+		value, ok := props[name]
+		if !ok {
+			return nil
+		}
+		if err := parseStructField(field, value); err != nil {
+			errs = append(errs, fmt.Errorf("DecodeInto: field %q: %w", name, err))
+		}
+		return nil
+	})
+	return errors.Join(errs...)
+}
+
+// parseStructField parses value into the field, allocating nil pointers.
+func parseStructField(field reflect.Value, value string) error {
+	if !field.CanSet() {
+		return errors.New("field cannot be set")
+	}
+	if field.Kind() == reflect.Pointer {
+		elem := reflect.New(field.Type().Elem())
+		if err := parseStructField(elem.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
+	switch field.Type() {
+	case timeType:
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
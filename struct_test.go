@@ -0,0 +1,162 @@
+package omni
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type structBase struct {
+	Lang string `omni:"lang"`
+}
+
+type structComponent struct {
+	structBase
+	Title    string        `omni:"title"`
+	Count    int           `omni:"count"`
+	Ratio    float64       `omni:"ratio"`
+	Visible  bool          `omni:"visible"`
+	Size     uint8         `omni:"size"`
+	Created  time.Time     `omni:"created"`
+	Timeout  time.Duration `omni:"timeout"`
+	Subtitle *string       `omni:"subtitle"`
+	Note     *string       `omni:"note"`
+	Internal string        `omni:"-"`
+	Name     string
+	hidden   string
+}
+
+func TestAtom_SetFromStruct(t *testing.T) {
+	subtitle := "Welcome"
+	created := time.Date(2024, 5, 1, 12, 30, 0, 5, time.UTC)
+	component := structComponent{
+		structBase: structBase{Lang: "en"},
+		Title:      "Home",
+		Count:      -3,
+		Ratio:      0.25,
+		Visible:    true,
+		Size:       200,
+		Created:    created,
+		Timeout:    90 * time.Second,
+		Subtitle:   &subtitle,
+		Internal:   "secret",
+		Name:       "hero",
+		hidden:     "hidden",
+	}
+
+	atom := NewAtom("component").(*Atom)
+	if err := atom.SetFromStruct(&component); err != nil {
+		t.Fatalf("SetFromStruct() error = %v", err)
+	}
+
+	want := map[string]string{
+		"lang":     "en",
+		"title":    "Home",
+		"count":    "-3",
+		"ratio":    "0.25",
+		"visible":  "true",
+		"size":     "200",
+		"created":  created.Format(time.RFC3339Nano),
+		"timeout":  "1m30s",
+		"subtitle": "Welcome",
+		"Name":     "hero",
+	}
+	got := atom.GetAll()
+	if len(got) != len(want) {
+		t.Fatalf("GetAll() = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("property %q = %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestAtom_SetFromStruct_Errors(t *testing.T) {
+	atom := NewAtom("component", WithProperty("title", "kept")).(*Atom)
+
+	unsupported := struct {
+		Title string
+		Tags  []string
+	}{Title: "new", Tags: []string{"a"}}
+	if err := atom.SetFromStruct(unsupported); err == nil || !strings.Contains(err.Error(), `field "Tags"`) {
+		t.Fatalf("SetFromStruct() error = %v, want an unsupported field error", err)
+	}
+	if atom.Get("title") != "kept" || atom.Has("Title") {
+		t.Fatal("no property should be set when a field is unsupported")
+	}
+
+	if err := atom.SetFromStruct("not a struct"); err == nil {
+		t.Fatal("expected an error for a non-struct value")
+	}
+	var nilComponent *structComponent
+	if err := atom.SetFromStruct(nilComponent); err == nil {
+		t.Fatal("expected an error for a nil pointer")
+	}
+}
+
+func TestAtom_DecodeInto_RoundTrip(t *testing.T) {
+	subtitle := "Welcome"
+	original := structComponent{
+		structBase: structBase{Lang: "en"},
+		Title:      "Home",
+		Count:      -3,
+		Ratio:      0.25,
+		Visible:    true,
+		Size:       200,
+		Created:    time.Date(2024, 5, 1, 12, 30, 0, 5, time.UTC),
+		Timeout:    90 * time.Second,
+		Subtitle:   &subtitle,
+		Name:       "hero",
+	}
+
+	atom := NewAtom("component").(*Atom)
+	if err := atom.SetFromStruct(original); err != nil {
+		t.Fatalf("SetFromStruct() error = %v", err)
+	}
+
+	var decoded structComponent
+	if err := atom.DecodeInto(&decoded); err != nil {
+		t.Fatalf("DecodeInto() error = %v", err)
+	}
+
+	if decoded.Lang != "en" || decoded.Title != "Home" || decoded.Count != -3 || decoded.Ratio != 0.25 ||
+		!decoded.Visible || decoded.Size != 200 || !decoded.Created.Equal(original.Created) ||
+		decoded.Timeout != 90*time.Second || decoded.Name != "hero" {
+		t.Fatalf("DecodeInto() = %+v, want %+v", decoded, original)
+	}
+	if decoded.Subtitle == nil || *decoded.Subtitle != "Welcome" || decoded.Note != nil {
+		t.Fatalf("unexpected pointer fields: %v, %v", decoded.Subtitle, decoded.Note)
+	}
+}
+
+func TestAtom_DecodeInto_MissingAndInvalid(t *testing.T) {
+	atom := NewAtom("component", WithProperties(map[string]string{
+		"title":   "Home",
+		"count":   "many",
+		"visible": "maybe",
+		"size":    "300",
+	})).(*Atom)
+
+	decoded := structComponent{Ratio: 1.5}
+	err := atom.DecodeInto(&decoded)
+	if err == nil {
+		t.Fatal("expected parse errors")
+	}
+	for _, field := range []string{`"count"`, `"visible"`, `"size"`} {
+		if !strings.Contains(err.Error(), field) {
+			t.Fatalf("error %q does not mention field %s", err, field)
+		}
+	}
+
+	if decoded.Title != "Home" {
+		t.Fatalf("Title = %q, want Home", decoded.Title)
+	}
+	if decoded.Ratio != 1.5 {
+		t.Fatal("fields without a property should be left unchanged")
+	}
+
+	if err := atom.DecodeInto(decoded); err == nil {
+		t.Fatal("expected an error for a non-pointer value")
+	}
+}
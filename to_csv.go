@@ -0,0 +1,79 @@
+package omni
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ToCSV flattens the immediate children of parent into CSV,
+// one row per child, which is useful for tabular data stored
+// as a parent atom with row children.
+//
+// Business logic:
+// - Returns an error if parent is nil
+// - The header row is the sorted union of all children's property keys
+// - Each non-nil child produces one data row, with blanks for missing keys
+// - Values containing commas, quotes or newlines are CSV-escaped
+// - Returns an empty string if there are no children
+//
+// Parameters:
+//   - parent: the atom whose children are the rows
+//
+// Returns:
+//   - string: the CSV data
+//   - error: if parent is nil or writing the CSV fails
+func ToCSV(parent AtomInterface) (string, error) {
+	if parent == nil {
+		return "", errors.New("parent atom cannot be nil")
+	}
+
+	rows := make([]map[string]string, 0, parent.ChildrenLength())
+	keySet := map[string]struct{}{}
+	for _, child := range parent.ChildrenGet() {
+		if child == nil {
+			continue
+		}
+		props := child.GetAll()
+		for key := range props {
+			keySet[key] = struct{}{}
+		}
+		rows = append(rows, props)
+	}
+
+	if len(rows) == 0 {
+		return "", nil
+	}
+
+	header := make([]string, 0, len(keySet))
+	for key := range keySet {
+		header = append(header, key)
+	}
+	sort.Strings(header)
+
+	var sb strings.Builder
+	writer := csv.NewWriter(&sb)
+
+	if err := writer.Write(header); err != nil {
+		return "", fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for i, props := range rows {
+		record := make([]string, len(header))
+		for j, key := range header {
+			record[j] = props[key]
+		}
+		if err := writer.Write(record); err != nil {
+			return "", fmt.Errorf("failed to write CSV row %d: %w", i, err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	return sb.String(), nil
+}
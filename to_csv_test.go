@@ -0,0 +1,39 @@
+package omni
+
+import "testing"
+
+func TestToCSV(t *testing.T) {
+	table := NewAtom("table")
+	table.ChildAdd(NewAtom("row", WithProperty("name", "Alice"), WithProperty("city", "Paris")))
+	table.ChildAdd(NewAtom("row", WithProperty("name", "Bob"), WithProperty("age", "42")))
+	table.ChildAdd(NewAtom("row", WithProperty("name", `Carol, "CJ"`), WithProperty("city", "Rome")))
+
+	got, err := ToCSV(table)
+	if err != nil {
+		t.Fatalf("ToCSV() error = %v", err)
+	}
+
+	want := "age,city,name\n" +
+		",Paris,Alice\n" +
+		"42,,Bob\n" +
+		",Rome,\"Carol, \"\"CJ\"\"\"\n"
+	if got != want {
+		t.Fatalf("ToCSV() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestToCSV_NoChildren(t *testing.T) {
+	got, err := ToCSV(NewAtom("table"))
+	if err != nil {
+		t.Fatalf("ToCSV() error = %v", err)
+	}
+	if got != "" {
+		t.Fatalf("expected empty CSV, got %q", got)
+	}
+}
+
+func TestToCSV_NilParent(t *testing.T) {
+	if _, err := ToCSV(nil); err == nil {
+		t.Fatal("expected error for nil parent, got nil")
+	}
+}
@@ -0,0 +1,47 @@
+package omni
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToDOT renders the tree rooted at root as a Graphviz DOT digraph,
+// which can be visualized with e.g. `dot -Tpng`.
+//
+// Each atom becomes a node whose DOT id is its (quoted) atom ID, so output
+// is stable across runs, and whose label shows the atom ID and type.
+// Edges connect each parent to its children, in order.
+// Nil atoms are skipped, so a nil root renders an empty digraph.
+func ToDOT(root AtomInterface) string {
+	var sb strings.Builder
+	sb.WriteString("digraph atoms {\n")
+	writeDOTNode(&sb, root)
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// writeDOTNode writes the node for the atom, its edges and its descendants to sb.
+func writeDOTNode(sb *strings.Builder, atom AtomInterface) {
+	if atom == nil {
+		return
+	}
+
+	id := dotQuote(atom.GetID())
+	label := dotQuote(atom.GetID() + "\n(" + atom.GetType() + ")")
+	fmt.Fprintf(sb, "  %s [label=%s];\n", id, label)
+
+	for _, child := range atom.ChildrenGet() {
+		if child == nil {
+			continue
+		}
+		fmt.Fprintf(sb, "  %s -> %s;\n", id, dotQuote(child.GetID()))
+		writeDOTNode(sb, child)
+	}
+}
+
+// dotQuote returns s as a double-quoted DOT string, escaping
+// backslashes, double quotes and newlines.
+func dotQuote(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", "")
+	return `"` + replacer.Replace(s) + `"`
+}
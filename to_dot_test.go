@@ -0,0 +1,40 @@
+package omni
+
+import "testing"
+
+func TestToDOT(t *testing.T) {
+	root := NewAtom("book", WithID("book1"))
+	page := NewAtom("page", WithID("page1"))
+	page.ChildAdd(NewAtom("text", WithID("t1")))
+	root.ChildAdd(page)
+
+	want := "digraph atoms {\n" +
+		"  \"book1\" [label=\"book1\\n(book)\"];\n" +
+		"  \"book1\" -> \"page1\";\n" +
+		"  \"page1\" [label=\"page1\\n(page)\"];\n" +
+		"  \"page1\" -> \"t1\";\n" +
+		"  \"t1\" [label=\"t1\\n(text)\"];\n" +
+		"}\n"
+
+	if got := ToDOT(root); got != want {
+		t.Fatalf("ToDOT() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestToDOT_EscapesLabels(t *testing.T) {
+	root := NewAtom(`a"b`, WithID(`id\"x`))
+
+	want := "digraph atoms {\n" +
+		"  \"id\\\\\\\"x\" [label=\"id\\\\\\\"x\\n(a\\\"b)\"];\n" +
+		"}\n"
+
+	if got := ToDOT(root); got != want {
+		t.Fatalf("ToDOT() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestToDOT_NilRoot(t *testing.T) {
+	if got := ToDOT(nil); got != "digraph atoms {\n}\n" {
+		t.Fatalf("unexpected output for nil root: %q", got)
+	}
+}
@@ -0,0 +1,102 @@
+package omni
+
+// AtomTx gives access to an atom while Transact holds its locks.
+// It exposes the same mutators as *Atom, applied directly to the atom,
+// and is only valid inside the function passed to Transact.
+type AtomTx struct {
+	atom *Atom
+}
+
+// Transact runs fn while holding the atom's write locks once, instead of
+// taking and releasing them for every mutation. Readers, including Snapshot,
+// never observe a partially applied transaction: they see the atom either
+// before or after all the mutations made through tx.
+//
+// Mutations are applied as they are made, and there is no rollback: if fn
+// panics, the mutations made so far are kept. Inside fn, use only tx to access
+// the atom; calling the atom's own methods would deadlock. Other atoms, such
+// as the children being added, can be used freely. Key watchers (see
+// OnChangeKey) are called once fn returns and the locks are released.
+func (a *Atom) Transact(fn func(tx *AtomTx)) {
+	a.mutateProperties(func() {
+		a.childrenMu.Lock()
+		defer a.childrenMu.Unlock()
+
+		fn(&AtomTx{atom: a})
+	})
+}
+
+// GetID returns the atom's ID.
+func (tx *AtomTx) GetID() string {
+	return tx.atom.id
+}
+
+// SetID sets the atom's ID.
+func (tx *AtomTx) SetID(id string) *AtomTx {
+	tx.atom.id = id
+	return tx
+}
+
+// GetType returns the atom's type.
+func (tx *AtomTx) GetType() string {
+	return tx.atom.atomType
+}
+
+// SetType sets the atom's type.
+func (tx *AtomTx) SetType(atomType string) *AtomTx {
+	tx.atom.atomType = atomType
+	return tx
+}
+
+// Get returns the value for the given key.
+func (tx *AtomTx) Get(key string) string {
+	return tx.atom.properties[key]
+}
+
+// Has checks if the given key exists.
+func (tx *AtomTx) Has(key string) bool {
+	_, ok := tx.atom.properties[key]
+	return ok
+}
+
+// Set sets the value for the given key, like (*Atom).Set.
+func (tx *AtomTx) Set(key, value string) *AtomTx {
+	_ = tx.atom.setLocked(key, value)
+	return tx
+}
+
+// SetChecked sets the value for the given key, like (*Atom).SetChecked.
+func (tx *AtomTx) SetChecked(key, value string) error {
+	return tx.atom.setLocked(key, value)
+}
+
+// Remove removes the value for the given key.
+func (tx *AtomTx) Remove(key string) *AtomTx {
+	delete(tx.atom.properties, key)
+	return tx
+}
+
+// ChildAdd adds a child atom, like (*Atom).ChildAdd.
+func (tx *AtomTx) ChildAdd(child AtomInterface) *AtomTx {
+	if child != nil {
+		tx.atom.childAddLocked(child)
+	}
+	return tx
+}
+
+// ChildDeleteByID removes the first immediate child with the given ID.
+func (tx *AtomTx) ChildDeleteByID(id string) *AtomTx {
+	tx.atom.childDeleteByIDLocked(id)
+	return tx
+}
+
+// ChildrenSet replaces the children, like (*Atom).ChildrenSet.
+func (tx *AtomTx) ChildrenSet(children []AtomInterface) *AtomTx {
+	tx.atom.childrenSetLocked(children)
+	return tx
+}
+
+// ChildrenLength returns the number of children.
+func (tx *AtomTx) ChildrenLength() int {
+	return len(tx.atom.children)
+}
@@ -0,0 +1,82 @@
+package omni
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestAtom_Transact_AppliesMutations(t *testing.T) {
+	a := NewAtom("doc", WithID("d1"), WithProperty("stale", "1")).(*Atom)
+	a.ChildAdd(NewAtom("old", WithID("old")))
+
+	a.Transact(func(tx *AtomTx) {
+		tx.SetType("document").
+			Set("title", "Home").
+			Remove("stale").
+			ChildDeleteByID("old").
+			ChildAdd(NewAtom("section", WithID("s1"))).
+			ChildAdd(nil)
+
+		if !tx.Has("title") || tx.Get("title") != "Home" || tx.ChildrenLength() != 1 {
+			t.Error("expected reads through tx to see earlier mutations")
+		}
+	})
+
+	if a.GetType() != "document" || a.Get("title") != "Home" || a.Has("stale") {
+		t.Fatalf("unexpected properties after Transact: %v", a)
+	}
+	if ids := a.ChildIDs(); len(ids) != 1 || ids[0] != "s1" {
+		t.Fatalf("unexpected children after Transact: %v", ids)
+	}
+}
+
+func TestAtom_Transact_RespectsAtomOptions(t *testing.T) {
+	a := NewAtom("doc", WithUniqueChildIDs(), WithPropertyHistory(5)).(*Atom)
+	a.Set("title", "v1")
+
+	a.Transact(func(tx *AtomTx) {
+		tx.Set("title", "v2")
+		tx.ChildAdd(NewAtom("item", WithID("dup")))
+		tx.ChildAdd(NewAtom("item", WithID("dup")))
+	})
+
+	if a.ChildrenLength() != 1 {
+		t.Fatalf("expected unique child IDs to be enforced, got %d children", a.ChildrenLength())
+	}
+	if history := a.History("title"); len(history) != 1 || history[0] != "v1" {
+		t.Fatalf("expected property history to be recorded, got %v", history)
+	}
+}
+
+func TestAtom_Transact_ReadersSeeAllOrNothing(t *testing.T) {
+	a := NewAtom("doc").(*Atom)
+	const updates = 20
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for round := 1; round <= 50; round++ {
+			a.Transact(func(tx *AtomTx) {
+				for i := 0; i < updates; i++ {
+					tx.Set(fmt.Sprintf("k%d", i), fmt.Sprint(round))
+				}
+			})
+		}
+	}()
+
+	for i := 0; i < 200; i++ {
+		snapshot := a.Snapshot()
+		props := snapshot.GetAll()
+		if len(props) != 0 && len(props) != updates {
+			t.Fatalf("observed a partial transaction with %d properties", len(props))
+		}
+		for _, v := range props {
+			if v != props["k0"] {
+				t.Fatalf("observed mixed rounds in one snapshot: %v", props)
+			}
+		}
+	}
+	wg.Wait()
+}
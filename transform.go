@@ -0,0 +1,43 @@
+package omni
+
+// Transform returns a new tree built by applying fn to a deep copy of every
+// atom in the tree rooted at root. The original tree is never mutated.
+//
+// Business logic:
+// - Atoms are transformed bottom-up, so fn sees already transformed children
+// - fn receives a fresh copy of each atom and may modify it freely
+// - fn returns the atom to use in the new tree, usually the one it received
+// - fn returning nil prunes that atom, with its whole subtree
+// - A nil fn makes a plain deep copy
+// - Returns nil if root is nil or fn prunes the root
+//
+// Parameters:
+//   - root: the root atom of the tree
+//   - fn: transforms one atom
+//
+// Returns:
+//   - AtomInterface: the root of the new tree, or nil
+func Transform(root AtomInterface, fn func(AtomInterface) AtomInterface) AtomInterface {
+	if isNilAtom(root) {
+		return nil
+	}
+
+	children := make([]AtomInterface, 0, root.ChildrenLength())
+	for _, child := range root.ChildrenGet() {
+		if transformed := Transform(child, fn); transformed != nil {
+			children = append(children, transformed)
+		}
+	}
+
+	atom := &Atom{
+		id:         root.GetID(),
+		atomType:   root.GetType(),
+		properties: root.GetAll(),
+	}
+	atom.setChildrenLocked(children)
+
+	if fn == nil {
+		return atom
+	}
+	return fn(atom)
+}
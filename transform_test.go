@@ -0,0 +1,72 @@
+package omni
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTransform_UppercasesWithoutMutatingOriginal(t *testing.T) {
+	root := NewAtom("page", WithID("p1"), WithProperty("title", "home"))
+	root.ChildAdd(NewAtom("text", WithID("t1"), WithProperty("title", "intro")))
+
+	result := Transform(root, func(atom AtomInterface) AtomInterface {
+		if atom.Has("title") {
+			atom.Set("title", strings.ToUpper(atom.Get("title")))
+		}
+		return atom
+	})
+
+	if result.Get("title") != "HOME" || result.ChildrenGet()[0].Get("title") != "INTRO" {
+		t.Fatalf("unexpected result: %v", result.ToMap())
+	}
+	if root.Get("title") != "home" || root.ChildrenGet()[0].Get("title") != "intro" {
+		t.Fatal("the original tree should not be mutated")
+	}
+	if result.ChildrenGet()[0] == root.ChildrenGet()[0] {
+		t.Fatal("the new tree should not share atoms with the original")
+	}
+}
+
+func TestTransform_BottomUpAndPruning(t *testing.T) {
+	root := NewAtom("page", WithID("p1"))
+	section := NewAtom("section", WithID("s1"))
+	section.ChildAdd(NewAtom("draft", WithID("d1")))
+	section.ChildAdd(NewAtom("text", WithID("t1")))
+	root.ChildAdd(section)
+
+	var order []string
+	result := Transform(root, func(atom AtomInterface) AtomInterface {
+		order = append(order, atom.GetID())
+		if atom.GetType() == "draft" {
+			return nil
+		}
+		return atom
+	})
+
+	if got := strings.Join(order, ","); got != "d1,t1,s1,p1" {
+		t.Fatalf("visit order = %s, want d1,t1,s1,p1", got)
+	}
+	children := result.ChildrenGet()[0].ChildrenGet()
+	if len(children) != 1 || children[0].GetID() != "t1" {
+		t.Fatalf("expected the draft to be pruned, got %v", children)
+	}
+	if section.ChildrenLength() != 2 {
+		t.Fatal("the original tree should keep the draft")
+	}
+}
+
+func TestTransform_NilInputs(t *testing.T) {
+	if Transform(nil, nil) != nil {
+		t.Fatal("expected nil for a nil root")
+	}
+
+	root := NewAtom("page", WithID("p1"))
+	if Transform(root, func(AtomInterface) AtomInterface { return nil }) != nil {
+		t.Fatal("expected nil when the root is pruned")
+	}
+
+	clone := Transform(root, nil)
+	if clone == root || !root.(*Atom).Equals(clone) {
+		t.Fatal("expected a nil fn to make a deep copy")
+	}
+}
@@ -0,0 +1,17 @@
+package omni
+
+// newTestTree returns the small tree shared by the tests of the tree-wide
+// features:
+//
+//	page p1 {title: Home, a/b: x}
+//	├── section s1 {name: intro}
+//	│   └── text t1 {content: Hello}
+//	└── text t2
+func newTestTree() *Atom {
+	root := NewAtom("page", WithID("p1"), WithProperties(map[string]string{"title": "Home", "a/b": "x"})).(*Atom)
+	section := NewAtom("section", WithID("s1"), WithProperty("name", "intro"))
+	section.ChildAdd(NewAtom("text", WithID("t1"), WithProperty("content", "Hello")))
+	root.ChildAdd(section)
+	root.ChildAdd(NewAtom("text", WithID("t2")))
+	return root
+}
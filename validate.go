@@ -0,0 +1,73 @@
+package omni
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Validate traverses the tree rooted at root and checks it for
+// structural problems that make lookups ambiguous or atoms unusable.
+//
+// Business logic:
+// - Returns an error if root is nil
+// - Reports every ID that appears more than once, with its occurrence count
+// - Reports every atom with an empty type
+// - Problems are listed in a deterministic order (sorted by ID)
+//
+// Parameters:
+//   - root: the root atom of the tree to validate
+//
+// Returns:
+//   - error: nil if the tree is valid, otherwise an error listing all problems
+func Validate(root AtomInterface) error {
+	if root == nil {
+		return errors.New("root atom cannot be nil")
+	}
+
+	counts := map[string]int{}
+	emptyTypes := []string{}
+	collectValidationInfo(root, counts, &emptyTypes)
+
+	problems := []string{}
+
+	duplicates := make([]string, 0)
+	for id, count := range counts {
+		if count > 1 {
+			duplicates = append(duplicates, id)
+		}
+	}
+	sort.Strings(duplicates)
+	for _, id := range duplicates {
+		problems = append(problems, fmt.Sprintf("duplicate id %q appears %d times", id, counts[id]))
+	}
+
+	sort.Strings(emptyTypes)
+	for _, id := range emptyTypes {
+		problems = append(problems, fmt.Sprintf("atom %q has empty type", id))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid atom tree: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// collectValidationInfo counts IDs and records the IDs of atoms
+// with an empty type for the atom and all its descendants.
+func collectValidationInfo(atom AtomInterface, counts map[string]int, emptyTypes *[]string) {
+	if atom == nil {
+		return
+	}
+
+	counts[atom.GetID()]++
+	if atom.GetType() == "" {
+		*emptyTypes = append(*emptyTypes, atom.GetID())
+	}
+
+	for _, child := range atom.ChildrenGet() {
+		collectValidationInfo(child, counts, emptyTypes)
+	}
+}
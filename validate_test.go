@@ -0,0 +1,50 @@
+package omni
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate_ValidTree(t *testing.T) {
+	root := NewAtom("root", WithID("root"))
+	root.ChildAdd(NewAtom("node", WithID("a")))
+	root.ChildAdd(NewAtom("node", WithID("b")))
+
+	if err := Validate(root); err != nil {
+		t.Fatalf("expected valid tree, got %v", err)
+	}
+}
+
+func TestValidate_DuplicateIDs(t *testing.T) {
+	root := NewAtom("root", WithID("root"))
+	a := NewAtom("node", WithID("dup"))
+	a.ChildAdd(NewAtom("leaf", WithID("dup")))
+	root.ChildAdd(a)
+	root.ChildAdd(NewAtom("node", WithID("dup")))
+
+	err := Validate(root)
+	if err == nil {
+		t.Fatal("expected error for duplicate ids, got nil")
+	}
+	if !strings.Contains(err.Error(), `duplicate id "dup" appears 3 times`) {
+		t.Fatalf("unexpected error message: %v", err)
+	}
+}
+
+func TestValidate_EmptyType(t *testing.T) {
+	root := NewAtom("root", WithID("root"))
+	child := NewAtom("node", WithID("untyped"))
+	child.SetType("")
+	root.ChildAdd(child)
+
+	err := Validate(root)
+	if err == nil || !strings.Contains(err.Error(), `atom "untyped" has empty type`) {
+		t.Fatalf("expected empty type error, got %v", err)
+	}
+}
+
+func TestValidate_NilRoot(t *testing.T) {
+	if err := Validate(nil); err == nil {
+		t.Fatal("expected error for nil root, got nil")
+	}
+}
@@ -0,0 +1,58 @@
+package omni
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// ErrValueTooLong is returned by SetChecked when a value exceeds the limit
+// set with WithMaxValueLength and the RejectLongValues policy.
+var ErrValueTooLong = errors.New("property value too long")
+
+// ValueLengthPolicy selects what WithMaxValueLength does with values over the limit.
+type ValueLengthPolicy int
+
+const (
+	// RejectLongValues rejects the write: Set ignores it, SetChecked
+	// returns an error wrapping ErrValueTooLong.
+	RejectLongValues ValueLengthPolicy = iota
+	// TruncateLongValues stores the value truncated to the limit,
+	// without splitting a UTF-8 encoded character.
+	TruncateLongValues
+)
+
+// WithMaxValueLength limits property values to maxLength bytes on every
+// property write (Set, SetChecked, SetIfAbsent, SetAll, PropertiesSet,
+// WithProperties, WithData, FromGob, UnmarshalJSON, ...), applying the given
+// policy to longer values. A maxLength of zero or less disables the limit.
+// Place it before WithProperties so the initial properties are also checked.
+func WithMaxValueLength(maxLength int, policy ValueLengthPolicy) AtomOption {
+	return func(a *Atom) {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.maxValueLength = maxLength
+		a.valueLengthPolicy = policy
+	}
+}
+
+// checkValueLengthLocked applies the value length limit, if any, to the
+// value of the given key. The caller must hold a.mu.
+func (a *Atom) checkValueLengthLocked(key, value string) (string, error) {
+	if a.maxValueLength <= 0 || len(value) <= a.maxValueLength {
+		return value, nil
+	}
+	if a.valueLengthPolicy == TruncateLongValues {
+		return truncateUTF8(value, a.maxValueLength), nil
+	}
+	return "", fmt.Errorf("%w: %q has %d bytes, limit is %d", ErrValueTooLong, key, len(value), a.maxValueLength)
+}
+
+// truncateUTF8 truncates s to at most n bytes, backing off to the start
+// of a character so a multi-byte character is never split.
+func truncateUTF8(s string, n int) string {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,133 @@
+package omni
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAtom_WithMaxValueLength_Reject(t *testing.T) {
+	a := NewAtom("item",
+		WithMaxValueLength(5, RejectLongValues),
+		WithProperties(map[string]string{"short": "abc", "long": "abcdef"}),
+	).(*Atom)
+
+	if a.Get("short") != "abc" || a.Has("long") {
+		t.Fatalf("expected initial long values to be skipped, got %v", a.GetAll())
+	}
+
+	err := a.SetChecked("title", "too long")
+	if !errors.Is(err, ErrValueTooLong) {
+		t.Fatalf("SetChecked() error = %v, want ErrValueTooLong", err)
+	}
+	a.Set("title", "too long")
+	if a.Has("title") {
+		t.Fatal("expected Set to ignore values over the limit")
+	}
+	if err := a.SetChecked("title", "12345"); err != nil || a.Get("title") != "12345" {
+		t.Fatalf("expected values at the limit to be accepted, err = %v", err)
+	}
+
+	a.SetAll(map[string]string{"a": "1", "b": "123456"})
+	if a.Get("title") != "12345" {
+		t.Fatal("expected SetAll with a long value to leave properties unchanged")
+	}
+}
+
+func TestAtom_WithMaxValueLength_AllWritePaths(t *testing.T) {
+	a := NewAtom("test",
+		WithMaxValueLength(3, RejectLongValues),
+		WithData(map[string]string{"short": "abc", "long": "toolongvalue"}),
+	).(*Atom)
+	if got := a.GetAll(); len(got) != 1 || got["short"] != "abc" {
+		t.Fatalf("expected WithData to skip long values, got %v", got)
+	}
+
+	if a.SetIfAbsent("k", "toolongvalue") || a.Has("k") {
+		t.Fatal("expected SetIfAbsent to reject long values")
+	}
+	if !a.SetIfAbsent("k", "ok") || a.Get("k") != "ok" {
+		t.Fatal("expected SetIfAbsent to store short values")
+	}
+
+	a.PropertiesSet([]PropertyInterface{NewProperty("a", "1"), NewProperty("b", "toolongvalue")})
+	if got := a.GetAll(); len(got) != 2 || got["k"] != "ok" {
+		t.Fatalf("expected PropertiesSet with a long value to leave properties unchanged, got %v", got)
+	}
+
+	err := a.SetAllChecked(map[string]string{"a": "toolongvalue"})
+	if !errors.Is(err, ErrValueTooLong) {
+		t.Fatalf("SetAllChecked() error = %v, want ErrValueTooLong", err)
+	}
+
+	truncating := NewAtom("test", WithMaxValueLength(3, TruncateLongValues)).(*Atom)
+	truncating.SetIfAbsent("k", "abcdef")
+	truncating.PropertiesSet([]PropertyInterface{NewProperty("p", "abcdef"), NewProperty("k", truncating.Get("k"))})
+	if got := truncating.GetAll(); got["k"] != "abc" || got["p"] != "abc" {
+		t.Fatalf("expected long values to be truncated, got %v", got)
+	}
+}
+
+func TestAtom_WithMaxValueLength_Decoders(t *testing.T) {
+	source := NewAtom("page", WithID("p1"), WithProperty("title", "toolongvalue")).(*Atom)
+	gobData, _ := source.ToGob()
+	jsonData, _ := source.ToJSON()
+
+	decoders := map[string]func(*Atom) error{
+		"FromGob":         func(a *Atom) error { return a.FromGob(gobData) },
+		"UnmarshalBinary": func(a *Atom) error { return a.UnmarshalBinary(gobData) },
+		"UnmarshalJSON":   func(a *Atom) error { return a.UnmarshalJSON([]byte(jsonData)) },
+		"UnmarshalText":   func(a *Atom) error { return a.UnmarshalText([]byte(jsonData)) },
+	}
+	for name, decode := range decoders {
+		rejecting := NewAtom("old", WithID("old"), WithMaxValueLength(3, RejectLongValues)).(*Atom)
+		if err := decode(rejecting); !errors.Is(err, ErrValueTooLong) {
+			t.Fatalf("%s() error = %v, want ErrValueTooLong", name, err)
+		}
+		if rejecting.GetID() != "old" || rejecting.Has("title") {
+			t.Fatalf("%s() should leave the atom unchanged on rejection, got %v", name, rejecting)
+		}
+
+		truncating := NewAtom("old", WithMaxValueLength(3, TruncateLongValues)).(*Atom)
+		if err := decode(truncating); err != nil || truncating.Get("title") != "too" {
+			t.Fatalf("%s() = %v, title %q, want the value truncated", name, err, truncating.Get("title"))
+		}
+	}
+
+	lower := NewAtom("old", WithKeyValidator(func(key string) (string, error) {
+		return strings.ToLower(key), nil
+	})).(*Atom)
+	if err := lower.UnmarshalJSON([]byte(`{"id":"p1","type":"page","properties":{"Title":"x"}}`)); err != nil || lower.Get("title") != "x" {
+		t.Fatalf("expected decoded keys to be normalized, got %v (%v)", lower.GetAll(), err)
+	}
+}
+
+func TestAtom_WithMaxValueLength_Truncate(t *testing.T) {
+	a := NewAtom("item", WithMaxValueLength(5, TruncateLongValues)).(*Atom)
+
+	if err := a.SetChecked("title", "abcdefgh"); err != nil {
+		t.Fatalf("SetChecked() error = %v", err)
+	}
+	if a.Get("title") != "abcde" {
+		t.Fatalf("Get(title) = %q, want truncated value", a.Get("title"))
+	}
+
+	// "ééé" is 6 bytes, so cutting at 5 would split the last character
+	a.Set("accents", "ééé")
+	if got := a.Get("accents"); got != "éé" {
+		t.Fatalf("Get(accents) = %q, want \"éé\"", got)
+	}
+
+	a.SetAll(map[string]string{"body": strings.Repeat("x", 10)})
+	if a.Get("body") != "xxxxx" {
+		t.Fatalf("expected SetAll to truncate, got %q", a.Get("body"))
+	}
+}
+
+func TestAtom_WithoutMaxValueLength(t *testing.T) {
+	a := NewAtom("item").(*Atom)
+	long := strings.Repeat("x", 1<<16)
+	if err := a.SetChecked("body", long); err != nil || a.Get("body") != long {
+		t.Fatalf("expected no limit by default, err = %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package omni
+
+// Visitor is implemented by passes over an atom tree.
+// Visit is called once for every atom by Accept.
+type Visitor interface {
+	Visit(atom AtomInterface)
+}
+
+// Accept calls visitor.Visit for every atom in the tree rooted at root,
+// in pre-order: the atom first, then its children in order.
+// Nil atoms are skipped.
+func Accept(root AtomInterface, visitor Visitor) {
+	if root == nil || visitor == nil {
+		return
+	}
+
+	visitor.Visit(root)
+
+	for _, child := range root.ChildrenGet() {
+		Accept(child, visitor)
+	}
+}
+
+// TypedVisitor is a Visitor that dispatches each atom to a handler
+// registered for its type, so passes don't need to switch on GetType.
+// Atoms without a registered handler go to the default handler, if any.
+type TypedVisitor struct {
+	handlers       map[string]func(AtomInterface)
+	defaultHandler func(AtomInterface)
+}
+
+var _ Visitor = (*TypedVisitor)(nil)
+
+// NewTypedVisitor creates a new TypedVisitor with no handlers.
+func NewTypedVisitor() *TypedVisitor {
+	return &TypedVisitor{handlers: make(map[string]func(AtomInterface))}
+}
+
+// On registers the handler for atoms of the given type.
+func (v *TypedVisitor) On(atomType string, handler func(AtomInterface)) *TypedVisitor {
+	v.handlers[atomType] = handler
+	return v
+}
+
+// Default registers the handler for atoms whose type has no handler.
+func (v *TypedVisitor) Default(handler func(AtomInterface)) *TypedVisitor {
+	v.defaultHandler = handler
+	return v
+}
+
+// Visit dispatches the atom to the handler registered for its type,
+// or to the default handler. Atoms with no matching handler are ignored.
+func (v *TypedVisitor) Visit(atom AtomInterface) {
+	if handler, ok := v.handlers[atom.GetType()]; ok && handler != nil {
+		handler(atom)
+		return
+	}
+	if v.defaultHandler != nil {
+		v.defaultHandler(atom)
+	}
+}
@@ -0,0 +1,62 @@
+package omni
+
+import (
+	"strings"
+	"testing"
+)
+
+type recordingVisitor struct {
+	ids []string
+}
+
+func (v *recordingVisitor) Visit(atom AtomInterface) {
+	v.ids = append(v.ids, atom.GetID())
+}
+
+func TestAccept_PreOrder(t *testing.T) {
+	root := NewAtom("root", WithID("root"))
+	a := NewAtom("node", WithID("a"))
+	a.ChildAdd(NewAtom("leaf", WithID("a1")))
+	root.ChildAdd(a)
+	root.ChildAdd(NewAtom("node", WithID("b")))
+
+	visitor := &recordingVisitor{}
+	Accept(root, visitor)
+
+	if got := strings.Join(visitor.ids, ","); got != "root,a,a1,b" {
+		t.Fatalf("unexpected visit order: %s", got)
+	}
+}
+
+func TestAccept_NilRoot(t *testing.T) {
+	visitor := &recordingVisitor{}
+	Accept(nil, visitor)
+	if len(visitor.ids) != 0 {
+		t.Fatalf("expected no visits, got %v", visitor.ids)
+	}
+}
+
+func TestTypedVisitor_Dispatch(t *testing.T) {
+	root := NewAtom("page", WithID("p"))
+	root.ChildAdd(NewAtom("header", WithID("h1")))
+	root.ChildAdd(NewAtom("paragraph", WithID("p1")))
+	root.ChildAdd(NewAtom("image", WithID("img1")))
+
+	var headers, paragraphs, others []string
+	visitor := NewTypedVisitor().
+		On("header", func(a AtomInterface) { headers = append(headers, a.GetID()) }).
+		On("paragraph", func(a AtomInterface) { paragraphs = append(paragraphs, a.GetID()) }).
+		Default(func(a AtomInterface) { others = append(others, a.GetID()) })
+
+	Accept(root, visitor)
+
+	if len(headers) != 1 || headers[0] != "h1" {
+		t.Fatalf("unexpected headers: %v", headers)
+	}
+	if len(paragraphs) != 1 || paragraphs[0] != "p1" {
+		t.Fatalf("unexpected paragraphs: %v", paragraphs)
+	}
+	if strings.Join(others, ",") != "p,img1" {
+		t.Fatalf("unexpected default visits: %v", others)
+	}
+}
@@ -0,0 +1,106 @@
+package omni
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// WalkParallel calls fn for every atom in the tree rooted at root, using a pool
+// of workers goroutines. It is WalkParallelContext with a background context.
+func WalkParallel(root AtomInterface, workers int, fn func(AtomInterface) error) error {
+	return WalkParallelContext(context.Background(), root, workers, fn)
+}
+
+// WalkParallelContext calls fn for every atom in the tree rooted at root,
+// fanning the atoms out to a pool of workers goroutines. This is useful
+// when the per-atom work is expensive (CPU-heavy or I/O bound).
+//
+// Business logic:
+// - Every atom is visited exactly once, but the order of processing is NOT guaranteed
+// - If workers is less than 1, runtime.GOMAXPROCS(0) workers are used
+// - The first error returned by fn stops the walk and is returned; atoms
+//   not yet handed to a worker are skipped
+// - If ctx is cancelled during the walk, it stops and ctx.Err() is returned
+// - Once the walk stops, fn is not called again, even for atoms already sent
+// - The function always waits for all started workers to finish before returning
+//
+// Parameters:
+//   - ctx: context for cancellation
+//   - root: the root atom of the tree to walk
+//   - workers: number of worker goroutines
+//   - fn: function called for each atom
+//
+// Returns:
+//   - error: the first error from fn, the context error, or nil
+func WalkParallelContext(ctx context.Context, root AtomInterface, workers int, fn func(AtomInterface) error) error {
+	if root == nil {
+		return nil
+	}
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		firstErr error
+		errOnce  sync.Once
+		wg       sync.WaitGroup
+	)
+	atoms := make(chan AtomInterface)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for atom := range atoms {
+				if ctx.Err() != nil {
+					continue
+				}
+				if err := fn(atom); err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+	// Feed atoms in pre-order until done or cancelled
+	stack := []AtomInterface{root}
+feed:
+	for len(stack) > 0 {
+		atom := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		// select picks randomly among ready cases, so check for a stop first
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case atoms <- atom:
+		case <-ctx.Done():
+			break feed
+		}
+
+		children := atom.ChildrenGet()
+		for i := len(children) - 1; i >= 0; i-- {
+			if children[i] != nil {
+				stack = append(stack, children[i])
+			}
+		}
+	}
+
+	close(atoms)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	// Workers skip atoms received after a cancellation, so any cancellation
+	// during the walk is reported, even one after the last atom was sent
+	return ctx.Err()
+}
@@ -0,0 +1,136 @@
+package omni
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func buildWalkTree(width, depth int) (AtomInterface, int) {
+	count := 0
+	var build func(depth int) AtomInterface
+	build = func(depth int) AtomInterface {
+		count++
+		atom := NewAtom("node", WithID(fmt.Sprintf("n%d", count)))
+		if depth > 0 {
+			for i := 0; i < width; i++ {
+				atom.ChildAdd(build(depth - 1))
+			}
+		}
+		return atom
+	}
+	root := build(depth)
+	return root, count
+}
+
+func TestWalkParallel_VisitsEveryAtomOnce(t *testing.T) {
+	root, total := buildWalkTree(4, 3)
+
+	var mu sync.Mutex
+	seen := map[string]int{}
+	err := WalkParallel(root, 8, func(atom AtomInterface) error {
+		mu.Lock()
+		seen[atom.GetID()]++
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkParallel() error = %v", err)
+	}
+
+	if len(seen) != total {
+		t.Fatalf("expected %d atoms visited, got %d", total, len(seen))
+	}
+	for id, count := range seen {
+		if count != 1 {
+			t.Fatalf("atom %s visited %d times", id, count)
+		}
+	}
+}
+
+func TestWalkParallel_ReturnsFirstError(t *testing.T) {
+	root, _ := buildWalkTree(4, 3)
+	boom := errors.New("boom")
+
+	err := WalkParallel(root, 4, func(atom AtomInterface) error {
+		if atom.GetID() == "n5" {
+			return boom
+		}
+		return nil
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected boom error, got %v", err)
+	}
+}
+
+func TestWalkParallelContext_Cancelled(t *testing.T) {
+	root, total := buildWalkTree(4, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var visited int32
+	err := WalkParallelContext(ctx, root, 1, func(atom AtomInterface) error {
+		if atomic.AddInt32(&visited, 1) == 3 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if int(atomic.LoadInt32(&visited)) >= total {
+		t.Fatalf("expected walk to stop early, visited %d of %d", visited, total)
+	}
+}
+
+func TestWalkParallel_NoCallsAfterStop(t *testing.T) {
+	root, _ := buildWalkTree(4, 3)
+	boom := errors.New("boom")
+
+	var calls int32
+	err := WalkParallel(root, 1, func(atom AtomInterface) error {
+		atomic.AddInt32(&calls, 1)
+		return boom
+	})
+	if !errors.Is(err, boom) || atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("expected one call returning boom, got %d calls and %v", calls, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = WalkParallelContext(ctx, root, 4, func(AtomInterface) error {
+		t.Error("fn should not be called with a cancelled context")
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWalkParallelContext_CancelledOnLastAtom(t *testing.T) {
+	root, total := buildWalkTree(4, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var visited int32
+	err := WalkParallelContext(ctx, root, 4, func(atom AtomInterface) error {
+		if int(atomic.AddInt32(&visited, 1)) == total {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled after cancelling on the last atom, got %v", err)
+	}
+}
+
+func TestWalkParallel_NilRoot(t *testing.T) {
+	err := WalkParallel(nil, 2, func(AtomInterface) error {
+		t.Fatal("fn should not be called for nil root")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected nil error for nil root, got %v", err)
+	}
+}
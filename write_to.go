@@ -0,0 +1,30 @@
+package omni
+
+import "io"
+
+// Compile-time check that *Atom satisfies io.WriterTo.
+var _ io.WriterTo = (*Atom)(nil)
+
+// WriteTo implements io.WriterTo, streaming the gob encoding of the atom
+// to w. The bytes written are identical to those returned by ToGob.
+//
+// The top-level atom is encoded directly into w, without building the
+// full byte slice first. Children are still encoded to byte slices, as
+// the gob format nests each child's encoding inside its parent.
+func (a *Atom) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	err := a.encodeGob(cw, true)
+	return cw.n, err
+}
+
+// countingWriter wraps an io.Writer and counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,55 @@
+package omni
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestAtom_WriteTo_MatchesToGob(t *testing.T) {
+	atom := NewAtom("page", WithID("p1"), WithProperty("title", "Home")).(*Atom)
+	atom.ChildAdd(NewAtom("section", WithID("s1")))
+
+	var buf bytes.Buffer
+	n, err := atom.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Fatalf("WriteTo() reported %d bytes, wrote %d", n, buf.Len())
+	}
+
+	want, err := atom.ToGob()
+	if err != nil {
+		t.Fatalf("ToGob() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Fatal("expected WriteTo output to match ToGob")
+	}
+
+	decoded, err := GobToAtom(buf.Bytes())
+	if err != nil {
+		t.Fatalf("GobToAtom() error = %v", err)
+	}
+	if decoded.GetID() != "p1" || decoded.ChildFindByID("s1") == nil {
+		t.Fatalf("unexpected decoded atom: %v", decoded)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestAtom_WriteTo_PropagatesWriterError(t *testing.T) {
+	atom := NewAtom("page", WithID("p1")).(*Atom)
+
+	n, err := atom.WriteTo(failingWriter{})
+	if err == nil {
+		t.Fatal("expected WriteTo to return the writer error")
+	}
+	if n != 0 {
+		t.Fatalf("expected 0 bytes written, got %d", n)
+	}
+}